@@ -0,0 +1,19 @@
+package bytepool
+
+// Notified of pool memory changes by capacity in bytes, such as to fold pool memory
+// into an application memory manager. Retained minus Reused approximates what the
+// pool holds, though sync.Pool drops during GC are not observable.
+// Methods are called concurrently and should be fast.
+type Accountant interface {
+	// A new backing array was allocated.
+	Allocated(size int)
+
+	// A released Bytes was kept for reuse.
+	Retained(size int)
+
+	// A kept Bytes was handed out again.
+	Reused(size int)
+
+	// A released Bytes was not kept.
+	Discarded(size int)
+}
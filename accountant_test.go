@@ -0,0 +1,57 @@
+package bytepool_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_accountant(t *testing.T) {
+	t.Parallel()
+
+	var lastDiff string
+	for range 1000 { // can have a buf dropped sometimes
+		a := &recordAccountant{}
+		pool := bytepool.NewBucketOptions([]int{4, 8}, bytepool.BucketPoolOptions{Accountant: a})
+
+		b := pool.GetGrown(3)
+		b.Release()
+		b = pool.GetGrown(3)
+		b.Release()
+		b = pool.GetGrown(9)
+		b.Release()
+
+		want := []string{
+			"allocated 4",
+			"retained 4",
+			"reused 4",
+			"retained 4",
+			"allocated 9",
+			"discarded 9",
+		}
+		lastDiff = cmp.Diff(want, a.events)
+		if lastDiff == "" {
+			return
+		}
+	}
+	t.Fatal(lastDiff)
+}
+
+type recordAccountant struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (a *recordAccountant) add(event string, size int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.events = append(a.events, fmt.Sprint(event, " ", size))
+}
+
+func (a *recordAccountant) Allocated(size int) { a.add("allocated", size) }
+func (a *recordAccountant) Retained(size int)  { a.add("retained", size) }
+func (a *recordAccountant) Reused(size int)    { a.add("reused", size) }
+func (a *recordAccountant) Discarded(size int) { a.add("discarded", size) }
@@ -0,0 +1,67 @@
+package bytepool
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// Provides the backing arrays of pooled Bytes, such as off-heap or mmap memory.
+//
+// Bytes allocated this way are freed once their pool drops them, so B must not be
+// retained after Release or after the Bytes becomes unreachable.
+type Allocator interface {
+	// Returned slice must have len=0 and cap=c.
+	Alloc(c int) []byte
+
+	// Called once b is no longer referenced by the pool.
+	// b might not have come from Alloc (callers can replace B), in which case it must be ignored.
+	Free(b []byte)
+}
+
+// Optionally implemented by an Allocator to return the memory of idle b to the OS, such as with
+// madvise(MADV_DONTNEED), keeping b usable. Returns false if b was not from Alloc or not released.
+// Released memory must read as zero when next used.
+type OSMemoryReleaser interface {
+	ReleaseOSMemory(b []byte) bool
+}
+
+// returned bytes have cap c and zero len. lost, when set, counts Bytes collected by GC.
+func allocSizedBytes(a Allocator, c int, p poolPutter, lost *lostCounts) *Bytes {
+	if a == nil && lost == nil {
+		return makeSizedBytes(c, p)
+	}
+	var b *Bytes
+	var base []byte // the allocated array rather than B, which callers can replace.
+	if a == nil {
+		b = makeSizedBytes(c, p)
+	} else {
+		base = a.Alloc(c)
+		b = &Bytes{
+			B:    base,
+			pool: p,
+			base: unsafe.SliceData(base),
+		}
+	}
+	// sync.Pool drops are silent, so freeing once the header is collected.
+	runtime.SetFinalizer(b, func(b *Bytes) {
+		if a != nil {
+			a.Free(base)
+		}
+		lost.add(b.lost)
+	})
+	return b
+}
+
+// start of b's array, identifying mapped regions.
+func regionKey(b []byte) uintptr {
+	return uintptr(unsafe.Pointer(unsafe.SliceData(b)))
+}
+
+// Frees the array of b, from allocSizedBytes with capacity c, now rather than once collected.
+// As the finalizer, frees from base as B can be resliced.
+func freeAllocated(a Allocator, b *Bytes, c int) {
+	runtime.SetFinalizer(b, nil)
+	a.Free(unsafe.Slice(b.base, c)[:0])
+	b.B = nil
+	b.pool = nil
+}
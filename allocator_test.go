@@ -0,0 +1,105 @@
+package bytepool_test
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_allocator(t *testing.T) {
+	t.Parallel()
+
+	a := &countAllocator{}
+	pool := bytepool.NewBucketOptions([]int{4, 8, 16}, bytepool.BucketPoolOptions{
+		Allocator:        a,
+		AllocatorMinSize: 8,
+	})
+
+	b := pool.GetGrown(3)
+	diffFatal(t, 4, cap(b.B))
+	b = pool.GetGrown(5)
+	diffFatal(t, 8, cap(b.B))
+	b = pool.GetFilled(16)
+	diffFatal(t, 16, len(b.B))
+	b = pool.GetGrown(17) // over
+	diffFatal(t, 17, cap(b.B))
+
+	diffFatal(t, []int{8, 16}, a.allocs())
+}
+
+func TestBucket_allocatorHugePage(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{1 << 10, 1 << 20}, bytepool.BucketPoolOptions{
+		Allocator: bytepool.NewHugePageAllocator(),
+	})
+	for range 10 {
+		b := pool.GetFilled(1 << 20)
+		diffFatal(t, 1<<20, cap(b.B))
+		b.B[len(b.B)-1] = 1
+		b.Release()
+	}
+}
+
+func TestBucket_allocatorFreesReplaced(t *testing.T) {
+	t.Parallel()
+
+	a := &freeAllocator{freed: make(chan *byte, 1)}
+	pool := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{Allocator: a})
+
+	data := func() *byte { // not keeping b reachable.
+		b := pool.GetGrown(8)
+		data := unsafe.SliceData(b.B)
+		b.B = make([]byte, 4)
+		return data
+	}()
+
+	for range 100 {
+		runtime.GC()
+		select {
+		case freed := <-a.freed:
+			if freed != data {
+				t.Fatal("freed replacing array")
+			}
+			return
+		case <-time.After(time.Millisecond):
+		}
+	}
+	t.Fatal("not freed")
+}
+
+type freeAllocator struct {
+	freed chan *byte
+}
+
+func (a *freeAllocator) Alloc(c int) []byte {
+	return make([]byte, 0, c)
+}
+
+func (a *freeAllocator) Free(b []byte) {
+	a.freed <- unsafe.SliceData(b)
+}
+
+type countAllocator struct {
+	mu    sync.Mutex
+	sizes []int
+}
+
+func (a *countAllocator) Alloc(c int) []byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sizes = append(a.sizes, c)
+	return make([]byte, 0, c)
+}
+
+func (a *countAllocator) Free([]byte) {}
+
+func (a *countAllocator) allocs() []int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.sizes
+}
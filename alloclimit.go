@@ -0,0 +1,178 @@
+package bytepool
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+)
+
+// Returned by TryGetGrown when a Get would allocate over AllocLimit.
+var ErrAllocLimited = errors.New("bytepool: allocation rate limited")
+
+type AllocPolicy int
+
+const (
+	// Blocks up to MaxWait for the rate to allow the allocation, then allocates regardless.
+	AllocWait AllocPolicy = iota
+
+	// GetGrownUpTo serves retained Bytes of the nearest smaller bucket, so capacity can be
+	// below the requested, allocating when none. Other Gets allocate.
+	AllocSmaller
+)
+
+// Caps allocations, misses and overs, per second. Beyond the cap Gets follow Policy and
+// TryGetGrown returns ErrAllocLimited, protecting against allocation storms such as after an
+// upstream changes payload sizes.
+type AllocLimit struct {
+	PerSecond int // zero is unlimited.
+	Burst     int // allocations allowed at once. Defaults to PerSecond.
+	Policy    AllocPolicy
+	MaxWait   time.Duration // with AllocWait. Defaults to 10ms.
+}
+
+// token bucket.
+type allocLimiter struct {
+	policy  AllocPolicy
+	maxWait time.Duration
+	perNano float64
+	burst   float64
+	limited counter
+	warn    *warner // nil without Logger.
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// nil when unlimited.
+func newAllocLimiter(o AllocLimit) *allocLimiter {
+	if o.PerSecond <= 0 {
+		return nil
+	}
+	if o.Burst <= 0 {
+		o.Burst = o.PerSecond
+	}
+	if o.MaxWait <= 0 {
+		o.MaxWait = 10 * time.Millisecond
+	}
+	return &allocLimiter{
+		policy:  o.Policy,
+		maxWait: o.MaxWait,
+		perNano: float64(o.PerSecond) / float64(time.Second),
+		burst:   float64(o.Burst),
+		tokens:  float64(o.Burst),
+		last:    time.Now(),
+	}
+}
+
+// must hold mu.
+func (l *allocLimiter) refill(now time.Time) {
+	l.tokens = min(l.burst, l.tokens+float64(now.Sub(l.last))*l.perNano)
+	l.last = now
+}
+
+// takes a token if available.
+func (l *allocLimiter) take() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(time.Now())
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// counts an allocation over the rate.
+func (l *allocLimiter) over() {
+	l.limited.Add(1)
+	l.warn.warn(warnLimited, "bytepool allocations over AllocLimit",
+		slog.Uint64("limited", l.limited.Load()),
+	)
+}
+
+// before an allocation, following the policy when over the rate. Nil is unlimited.
+func (l *allocLimiter) admit() {
+	if l == nil || l.take() {
+		return
+	}
+	l.over()
+	if l.policy != AllocWait {
+		return
+	}
+
+	l.mu.Lock()
+	l.refill(time.Now())
+	wait := time.Duration((1 - l.tokens) / l.perNano)
+	if wait <= l.maxWait {
+		l.tokens-- // reserved, repaid by the refill while waiting.
+	} else {
+		wait = l.maxWait
+	}
+	l.mu.Unlock()
+
+	time.Sleep(wait)
+}
+
+// Bytes with zero length and minimum capacity c as GetGrown, or ErrAllocLimited rather than
+// allocating over AllocLimit, or ErrMemoryLimit over a MemoryLimit Hard limit with FailGets.
+func (p *BucketPool) TryGetGrown(c int) (*Bytes, error) {
+	_, sp := p.findPool(c)
+	if sp != nil {
+		if b := sp.getNoAlloc(p); b != nil {
+			return b, nil
+		}
+	}
+	if p.mem.failGetsOverHard() {
+		return nil, ErrMemoryLimit
+	}
+	if l := p.limit; l != nil && !l.take() {
+		l.over()
+		return nil, ErrAllocLimited
+	}
+	if sp == nil {
+		p.over(c, false)
+		return p.makeOverAdmitted(c), nil
+	}
+	return sp.allocateAdmitted(p), nil
+}
+
+// As GetGrown, though over AllocLimit with AllocSmaller serves retained Bytes of the nearest
+// smaller bucket rather than allocating, so cap can be below c. For callers that can make do
+// with less, such as reading in pieces, and check cap.
+func (p *BucketPool) GetGrownUpTo(c int) *Bytes {
+	idx, sp := p.findPool(c)
+	if sp == nil || p.limit == nil || p.limit.policy != AllocSmaller {
+		return p.GetGrown(c)
+	}
+	if b := sp.getNoAlloc(p); b != nil {
+		return b
+	}
+	if b := p.getLarger(idx, math.MaxInt); b != nil {
+		return b
+	}
+	if b := p.smallerOverLimit(idx); b != nil {
+		return b
+	}
+	return sp.allocateAdmitted(p)
+}
+
+func (g *BucketPooler) GetGrownUpTo(c int) *Bytes {
+	return g.pool.GetGrownUpTo(c)
+}
+
+// for GetGrownUpTo, nil when allowed to allocate.
+func (p *BucketPool) smallerOverLimit(idx int) *Bytes {
+	if !p.limit.take() {
+		p.limit.over()
+		for i := idx - 1; i >= 0; i-- {
+			if b := p.pools[i].getNoAlloc(p); b != nil {
+				return b
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package bytepool_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_allocLimit(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	limited := func(policy bytepool.AllocPolicy) *bytepool.BucketPool {
+		return bytepool.NewBucketOptions([]int{8, 16}, bytepool.BucketPoolOptions{
+			MaxRetained: 10,
+			AllocLimit:  bytepool.AllocLimit{PerSecond: 1, Policy: policy, MaxWait: time.Millisecond},
+		})
+	}
+
+	t.Run("try", func(t *testing.T) {
+		t.Parallel()
+
+		pool := limited(bytepool.AllocWait)
+		b, err := pool.TryGetGrown(8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := pool.TryGetGrown(8); !errors.Is(err, bytepool.ErrAllocLimited) {
+			t.Fatal(err)
+		}
+		if _, err := pool.TryGetGrown(100); !errors.Is(err, bytepool.ErrAllocLimited) {
+			t.Fatal(err)
+		}
+		b.Release()
+		if _, err := pool.TryGetGrown(8); err != nil { // retained, no allocation
+			t.Fatal(err)
+		}
+		diffFatal(t, uint64(2), pool.Stats().Limited)
+	})
+	t.Run("wait", func(t *testing.T) {
+		t.Parallel()
+
+		pool := limited(bytepool.AllocWait)
+		pool.GetGrown(8)
+		start := time.Now()
+		pool.GetGrown(8) // waits MaxWait as a token is a second away, then allocates
+		pool.GetGrown(100)
+		if time.Since(start) < 2*time.Millisecond {
+			t.Fatal(time.Since(start))
+		}
+		s := pool.Stats()
+		diffFatal(t, [2]uint64{2, 2}, [2]uint64{s.Limited, s.Misses})
+	})
+	t.Run("smaller", func(t *testing.T) {
+		t.Parallel()
+
+		pool := limited(bytepool.AllocSmaller)
+		pool.GetGrown(8).Release()
+
+		b := pool.GetGrownUpTo(16)
+		diffFatal(t, 8, cap(b.B)) // smaller retained
+		b = pool.GetGrownUpTo(16)
+		diffFatal(t, 16, cap(b.B)) // none retained, allocated
+		diffFatal(t, uint64(2), pool.Stats().Limited)
+
+		b.Release()
+		b = pool.GetGrown(32)
+		diffFatal(t, 32, cap(b.B)) // GetGrown keeps cap >= c, allocating.
+		diffFatal(t, uint64(3), pool.Stats().Limited)
+	})
+}
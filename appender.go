@@ -0,0 +1,59 @@
+package bytepool
+
+// Appends into pooled Bytes, regrowing by fetching a larger Bytes from the pool and
+// releasing the outgrown one, rather than append reallocating outside the pool. Past the
+// pool's buckets capacity doubles as with append. Call Release or Take when done.
+type Appender struct {
+	p SizedPooler
+	b *Bytes
+}
+
+func NewAppender(p SizedPooler) *Appender {
+	return &Appender{p: p}
+}
+
+// Ensures room for n more bytes.
+func (a *Appender) Grow(n int) {
+	a.b = GrowPooled(a.p, a.b, a.Len()+n)
+}
+
+func (a *Appender) Append(data ...byte) {
+	a.Grow(len(data))
+	a.b.B = append(a.b.B, data...)
+}
+
+func (a *Appender) AppendString(s string) {
+	a.Grow(len(s))
+	a.b.B = append(a.b.B, s...)
+}
+
+func (a *Appender) Write(p []byte) (int, error) {
+	a.Append(p...)
+	return len(p), nil
+}
+
+func (a *Appender) Len() int {
+	return bytesLen(a.b)
+}
+
+// Appended bytes, valid until the next append, Take or Release.
+func (a *Appender) Bytes() []byte {
+	if a.b == nil {
+		return nil
+	}
+	return a.b.B
+}
+
+// Hands the Bytes to the caller, who must Release it, and resets the Appender.
+// Can return nil if nothing was appended.
+func (a *Appender) Take() *Bytes {
+	b := a.b
+	a.b = nil
+	return b
+}
+
+// Returns the Bytes to the pool and resets the Appender.
+func (a *Appender) Release() {
+	a.b.Release()
+	a.b = nil
+}
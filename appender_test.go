@@ -0,0 +1,60 @@
+package bytepool_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestAppender(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(4, 128)
+	a := bytepool.NewAppender(pool)
+
+	diffFatal(t, 0, a.Len())
+	diffFatal(t, []byte(nil), a.Bytes())
+
+	var want []byte
+	for i := range 30 {
+		a.Append(byte(i))
+		a.AppendString("s")
+		fmt.Fprint(a, "w")
+		want = append(want, byte(i), 's', 'w')
+
+		// grows through bucket sizes
+		if c := cap(a.Bytes()); c&(c-1) != 0 {
+			t.Fatal(c)
+		}
+	}
+	diffFatal(t, want, a.Bytes())
+	diffFatal(t, len(want), a.Len())
+
+	b := a.Take()
+	diffFatal(t, want, b.B)
+	diffFatal(t, 0, a.Len())
+	b.Release()
+
+	a.Append(1, 2)
+	diffFatal(t, []byte{1, 2}, a.Bytes())
+	a.Release()
+	diffFatal(t, 0, a.Len())
+
+	s := pool.Stats()
+	diffFatal(t, uint64(0), s.Overs)
+}
+
+func TestAppender_pastBuckets(t *testing.T) {
+	t.Parallel()
+
+	a := bytepool.NewAppender(bytepool.NewBucket(4, 128))
+	defer a.Release()
+
+	caps := map[int]bool{}
+	for range 1000 {
+		a.Append(1)
+		caps[cap(a.Bytes())] = true
+	}
+	diffFatal(t, map[int]bool{4: true, 8: true, 16: true, 32: true, 64: true, 128: true, 256: true, 512: true, 1024: true}, caps)
+}
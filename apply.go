@@ -0,0 +1,46 @@
+package bytepool
+
+// Changes MaxRetained and MemoryLimit Soft and Hard of a running pool, keeping retained Bytes
+// that still fit. Other fields are ignored. Zero fields keep their current value.
+// MaxRetained only applies to pools constructed with it, lowering it trims the least recently
+// used Bytes. MemoryLimit only applies to pools constructed with it, with Weights kept. A
+// negative Soft or Hard removes that limit. Lowering either evicts or drops only on later puts,
+// Bytes already retained under Hard stay until got.
+func (p *BucketPool) ApplyOptions(o BucketPoolOptions) {
+	if o.MaxRetained > 0 {
+		for _, sp := range p.pools {
+			if sp.list == nil {
+				continue
+			}
+			p.trimmed(sp, sp.list.resize(o.MaxRetained))
+		}
+	}
+
+	if p.mem != nil && (o.MemoryLimit.Soft != 0 || o.MemoryLimit.Hard != 0) {
+		soft, hard := p.mem.soft.Load(), p.mem.hard.Load()
+		if o.MemoryLimit.Soft != 0 {
+			soft = o.MemoryLimit.Soft
+		}
+		if o.MemoryLimit.Hard != 0 {
+			hard = o.MemoryLimit.Hard
+		}
+		p.mem.setLimits(soft, hard) // negatives as zero, unlimited.
+		for _, sp := range p.pools {
+			if sp.mem != nil && sp.mem.parent != nil {
+				sp.mem.apportion()
+			}
+		}
+	}
+}
+
+// Changes ChooseInc, Decay, Decayer, MaxPoolPuts and BinChecks of a running pooler, keeping its
+// put counts and default size. Zero fields take their defaults, as with Pooler. Decay, Decayer
+// and MaxPoolPuts only apply to the default Chooser. Chooser and Predictor are ignored.
+func (g *BucketPooler) ApplyOptions(o BucketPoolerOptions) {
+	o = poolerDefaults(o)
+	if c, ok := g.chooser.(*decayChooser); ok {
+		c.apply(o)
+	}
+	g.binChecks.Store(int64(o.BinChecks))
+	g.chooseInc.Store(int64(o.ChooseInc))
+}
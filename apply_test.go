@@ -0,0 +1,80 @@
+package bytepool_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_ApplyOptions(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{100}, bytepool.BucketPoolOptions{
+		MaxRetained: 4,
+		MemoryLimit: bytepool.MemoryLimit{Hard: 1000},
+	})
+	release := func(n int) {
+		var held []*bytepool.Bytes
+		for range n {
+			held = append(held, pool.GetGrown(100))
+		}
+		for _, b := range held {
+			b.Release()
+		}
+	}
+
+	release(4)
+	pool.ApplyOptions(bytepool.BucketPoolOptions{MaxRetained: 2, MemoryLimit: bytepool.MemoryLimit{Hard: 1000}})
+	s := pool.Stats()
+	diffFatal(t, [3]int{2, 2, 200}, [3]int{s.Pooled, int(s.Trimmed), int(s.Memory)})
+
+	pool.ApplyOptions(bytepool.BucketPoolOptions{MaxRetained: 3, MemoryLimit: bytepool.MemoryLimit{Hard: 250}})
+	release(3) // the third put is over hard.
+	s = pool.Stats()
+	diffFatal(t, [3]int{2, 1, 200}, [3]int{s.Pooled, int(s.HardDrops), int(s.Memory)})
+
+	pool.ApplyOptions(bytepool.BucketPoolOptions{MaxRetained: 3}) // limits unchanged.
+	release(3)
+	s = pool.Stats()
+	diffFatal(t, [2]int{2, 2}, [2]int{s.Pooled, int(s.HardDrops)})
+
+	pool.ApplyOptions(bytepool.BucketPoolOptions{MemoryLimit: bytepool.MemoryLimit{Hard: -1}}) // unlimited memory.
+	release(3)
+	diffFatal(t, 3, pool.Stats().Pooled)
+
+	pool.ApplyOptions(bytepool.BucketPoolOptions{MemoryLimit: bytepool.MemoryLimit{Hard: 100}})
+	diffFatal(t, 3, pool.Stats().Pooled) // kept until got.
+	release(1)                           // got, then dropped over hard.
+	s = pool.Stats()
+	diffFatal(t, [2]int{2, 3}, [2]int{s.Pooled, int(s.HardDrops)})
+}
+
+func TestBucketPooler_ApplyOptions(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions(bytepool.Pow2Sizes(8, 64), bytepool.BucketPoolOptions{MaxRetained: 4}) // sync.Pool drops puts under race.
+	pooler := pool.Pooler(bytepool.BucketPoolerOptions{ChooseInc: 1000})
+
+	release := func(l, n int) {
+		for range n {
+			b := pooler.Get()
+			b.B = append(b.B, make([]byte, l)...)
+			b.Release()
+		}
+	}
+
+	release(32, 100) // past the initial ramp.
+	diffFatal(t, 32, pooler.Stats().DefaultSize)
+	release(8, 100)
+	diffFatal(t, 32, pooler.Stats().DefaultSize)
+
+	pooler.ApplyOptions(bytepool.BucketPoolerOptions{ChooseInc: 10, Decayer: bytepool.WindowDecay(), BinChecks: 1})
+	release(8, 10)
+	diffFatal(t, 8, pooler.Stats().DefaultSize)
+
+	pooler.ResetStats()
+	release(8, 10)
+	diffFatal(t, []uint64{10}, pooler.Stats().HitOffsets)
+}
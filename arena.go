@@ -0,0 +1,86 @@
+package bytepool
+
+// Hands out Bytes carved from pooled slabs, all freed at once with Free, such as at the end of
+// a request. Suited to many short lived buffers with the same lifetime, avoiding per Bytes pool
+// traffic. Release of carved Bytes does nothing. Not safe for concurrent use.
+type Arena struct {
+	pool     SizedPooler
+	slabSize int
+
+	slabs   []*Bytes // last is current.
+	off     int      // into current slab.
+	large   []*Bytes // over slabSize, taken directly from pool.
+	headers [][]Bytes
+	used    int // headers handed out.
+}
+
+const arenaHeaderChunk = 32
+
+// slabSize is the capacity taken from p per slab, larger Gets take their own Bytes from p.
+// Panics if slabSize < 1.
+func NewArena(p SizedPooler, slabSize int) *Arena {
+	if slabSize < 1 {
+		panic("slabSize < 1")
+	}
+	return &Arena{pool: p, slabSize: slabSize}
+}
+
+// Bytes with zero length and capacity c, valid until Free.
+// Appending over c reallocates rather than overwriting other Bytes.
+func (a *Arena) GetGrown(c int) *Bytes {
+	return a.header(a.carve(max(0, c))[:0])
+}
+
+// Bytes with length, valid until Free.
+func (a *Arena) GetFilled(length int) *Bytes {
+	return a.header(a.carve(max(0, length)))
+}
+
+// Returns every slab to the pool, invalidating all Bytes from the Arena.
+// The Arena can be used again after.
+func (a *Arena) Free() {
+	for _, b := range a.slabs {
+		b.Release()
+	}
+	for _, b := range a.large {
+		b.Release()
+	}
+	clear(a.slabs)
+	clear(a.large)
+	a.slabs = a.slabs[:0]
+	a.large = a.large[:0]
+	a.off = 0
+
+	for i := range a.used {
+		a.headers[i/arenaHeaderChunk][i%arenaHeaderChunk] = Bytes{}
+	}
+	a.used = 0
+}
+
+// len and cap c.
+func (a *Arena) carve(c int) []byte {
+	if c > a.slabSize {
+		b := a.pool.GetGrown(c)
+		a.large = append(a.large, b)
+		return b.B[:c:c]
+	}
+	if len(a.slabs) == 0 || a.off+c > a.slabSize {
+		a.slabs = append(a.slabs, a.pool.GetGrown(a.slabSize))
+		a.off = 0
+	}
+	slab := a.slabs[len(a.slabs)-1].B[:a.slabSize]
+	s := slab[a.off : a.off+c : a.off+c]
+	a.off += c
+	return s
+}
+
+func (a *Arena) header(b []byte) *Bytes {
+	if a.used == len(a.headers)*arenaHeaderChunk {
+		a.headers = append(a.headers, make([]Bytes, arenaHeaderChunk))
+	}
+	h := &a.headers[a.used/arenaHeaderChunk][a.used%arenaHeaderChunk]
+	h.B = b
+	h.view = true
+	a.used++
+	return h
+}
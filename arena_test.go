@@ -0,0 +1,74 @@
+package bytepool_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestArena(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucket(8, 64)
+	arena := bytepool.NewArena(pool, 64)
+
+	var held []*bytepool.Bytes
+	for i := range 20 {
+		b := arena.GetFilled(i % 10)
+		diffFatal(t, i%10, len(b.B))
+		diffFatal(t, i%10, cap(b.B))
+		for j := range b.B {
+			b.B[j] = byte(i)
+		}
+		b.Release() // no-op
+		held = append(held, b)
+	}
+	for i, b := range held {
+		for _, v := range b.B {
+			if v != byte(i) {
+				t.Fatal("aliased", i)
+			}
+		}
+	}
+
+	b := arena.GetGrown(3)
+	diffFatal(t, 0, len(b.B))
+	b.B = append(b.B, 1, 2, 3, 4) // reallocates, not over the next
+	next := arena.GetFilled(1)
+	next.B[0] = 9
+	diffFatal(t, []byte{1, 2, 3, 4}, b.B)
+
+	large := arena.GetGrown(100)
+	if cap(large.B) < 100 {
+		t.Fatal(cap(large.B))
+	}
+
+	arena.Free()
+	s := pool.Stats()
+	diffFatal(t, uint64(3), s.Gets) // 2 slabs and the large
+	diffFatal(t, uint64(3), s.Puts)
+	diffFatal(t, uint64(2), s.Overs) // get and put
+
+	diffFatal(t, 5, len(arena.GetFilled(5).B))
+	arena.Free()
+}
+
+func TestArena_noAllocs(t *testing.T) { // not parallel for AllocsPerRun.
+	arena := bytepool.NewArena(bytepool.NewBucketOptions([]int{1024}, bytepool.BucketPoolOptions{MaxRetained: 10}), 1024)
+
+	run := func() {
+		for i := range 100 {
+			b := arena.GetFilled(i % 50)
+			if len(b.B) != i%50 {
+				t.Fatal(b)
+			}
+		}
+		arena.Free()
+	}
+	run() // warm
+
+	if allocs := testing.AllocsPerRun(100, run); allocs != 0 {
+		t.Fatal(allocs)
+	}
+}
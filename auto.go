@@ -0,0 +1,173 @@
+package bytepool
+
+import (
+	"math/bits"
+	"sync/atomic"
+)
+
+type AutoKind int
+
+const (
+	AutoMeasuring AutoKind = iota // as NewDynamic while observing sizes.
+	AutoSync                      // NewSync, for similar sizes.
+	AutoDynamic                   // NewDynamic, for moderately spread sizes.
+	AutoBucket                    // a BucketPooler over Pow2Sizes, for widely spread sizes.
+)
+
+func (k AutoKind) String() string {
+	switch k {
+	case AutoMeasuring:
+		return "measuring"
+	case AutoSync:
+		return "sync"
+	case AutoDynamic:
+		return "dynamic"
+	case AutoBucket:
+		return "bucket"
+	}
+	return "unknown"
+}
+
+type AutoOptions struct {
+	Samples int // released lengths observed before choosing. Defaults to 1000.
+
+	// Only reports the choice in Stats, staying as AutoMeasuring, such as to learn which
+	// constructor to use.
+	ReportOnly bool
+}
+
+// Measures released lengths then switches to the implementation suiting them: AutoSync when
+// the 5th to 95th percentiles are within 2x, AutoBucket when 16x or more, otherwise AutoDynamic.
+// Bytes got before the switch are released to the chosen implementation.
+type AutoPool struct {
+	samples    int64
+	reportOnly bool
+
+	puts    atomic.Int64
+	lens    [bits.UintSize + 1]atomic.Uint64 // by bits.Len of len-1, so bin i holds up to 1<<i.
+	backend atomic.Pointer[autoBackend]
+	chosen  atomic.Pointer[autoBackend] // differs from backend with ReportOnly.
+}
+
+type autoBackend struct {
+	pool  Pool
+	kind  AutoKind
+	sizes SizeSet // with AutoBucket.
+}
+
+func NewAuto(o AutoOptions) *AutoPool {
+	if o.Samples <= 0 {
+		o.Samples = 1000
+	}
+	a := &AutoPool{samples: int64(o.Samples), reportOnly: o.ReportOnly}
+	measuring := &autoBackend{pool: NewDynamic(), kind: AutoMeasuring}
+	a.backend.Store(measuring)
+	a.chosen.Store(measuring)
+	return a
+}
+
+func (a *AutoPool) Get() *Bytes {
+	be := a.backend.Load()
+	return a.own(be, be.pool.Get())
+}
+
+func (a *AutoPool) GetGrown(c int) *Bytes {
+	be := a.backend.Load()
+	return a.own(be, be.pool.GetGrown(c))
+}
+
+func (a *AutoPool) GetFilled(length int) *Bytes {
+	be := a.backend.Load()
+	return a.own(be, be.pool.GetFilled(length))
+}
+
+func (a *AutoPool) Put(b *Bytes) {
+	if b != nil {
+		adopt(b, a)
+		b.Release()
+	}
+}
+
+func (a *AutoPool) Adopt(b *Bytes) {
+	if b != nil {
+		adopt(b, a)
+	}
+}
+
+type AutoPoolStats struct {
+	Kind    AutoKind // chosen, AutoMeasuring until Samples.
+	Samples int
+	Sizes   SizeSet // with AutoBucket.
+}
+
+func (a *AutoPool) Stats() AutoPoolStats {
+	be := a.chosen.Load()
+	return AutoPoolStats{
+		Kind:    be.kind,
+		Samples: int(min(a.puts.Load(), a.samples)),
+		Sizes:   be.sizes,
+	}
+}
+
+// releases while measuring are observed.
+func (a *AutoPool) own(be *autoBackend, b *Bytes) *Bytes {
+	if be.kind == AutoMeasuring {
+		b.pool = a
+	}
+	return b
+}
+
+func (a *AutoPool) put(b *Bytes) {
+	if b == nil {
+		return
+	}
+	be := a.backend.Load()
+	if be.kind == AutoMeasuring && len(b.B) > 0 {
+		if n := a.puts.Add(1); n <= a.samples {
+			a.lens[bits.Len(uint(len(b.B)-1))].Add(1)
+			if n == a.samples {
+				a.choose()
+			}
+		}
+	}
+	be.pool.Put(b)
+}
+
+func (a *AutoPool) choose() {
+	var total uint64
+	for i := range a.lens {
+		total += a.lens[i].Load()
+	}
+	// bins at the 5th and 95th percentiles.
+	lo, hi := -1, 0
+	var seen uint64
+	for i := range a.lens {
+		seen += a.lens[i].Load()
+		if lo < 0 && seen*20 > total {
+			lo = i
+		}
+		if seen*20 >= total*19 {
+			hi = i
+			break
+		}
+	}
+
+	var be *autoBackend
+	switch spread := hi - lo; {
+	case spread <= 1:
+		be = &autoBackend{pool: NewSync(), kind: AutoSync}
+	case spread >= 4:
+		sizes := Pow2Sizes(1<<lo, 1<<hi)
+		be = &autoBackend{
+			pool:  NewBucketFull(sizes).Pooler(BucketPoolerOptions{}),
+			kind:  AutoBucket,
+			sizes: sizes,
+		}
+	default:
+		be = &autoBackend{pool: NewDynamic(), kind: AutoDynamic}
+	}
+	a.chosen.Store(be)
+	if !a.reportOnly {
+		a.backend.Store(be)
+	}
+}
@@ -0,0 +1,53 @@
+package bytepool_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestAutoPool(t *testing.T) {
+	t.Parallel()
+
+	release := func(a *bytepool.AutoPool, lens ...int) {
+		for range 100 {
+			for _, l := range lens {
+				b := a.Get()
+				b.B = append(b.B, make([]byte, l)...)
+				b.Release()
+			}
+		}
+	}
+
+	cases := []struct {
+		name  string
+		lens  []int
+		kind  bytepool.AutoKind
+		sizes bytepool.SizeSet
+	}{
+		{"uniform", []int{100, 120}, bytepool.AutoSync, nil},
+		{"moderate", []int{100, 300, 600}, bytepool.AutoDynamic, nil},
+		{"wide", []int{10, 100, 1000, 10000}, bytepool.AutoBucket, bytepool.SizeSet{16, 32, 64, 128, 256, 512, 1024, 2048, 4096, 8192, 16384}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := bytepool.NewAuto(bytepool.AutoOptions{Samples: 50})
+			var _ bytepool.Pool = a
+			diffFatal(t, bytepool.AutoPoolStats{}, a.Stats())
+
+			held := a.GetGrown(5) // released after the switch
+			release(a, c.lens...)
+			held.Release()
+			diffFatal(t, bytepool.AutoPoolStats{Kind: c.kind, Samples: 50, Sizes: c.sizes}, a.Stats())
+			diffFatal(t, true, cap(a.GetGrown(7).B) >= 7)
+
+			report := bytepool.NewAuto(bytepool.AutoOptions{Samples: 50, ReportOnly: true})
+			release(report, c.lens...)
+			diffFatal(t, c.kind, report.Stats().Kind)
+		})
+	}
+
+	diffFatal(t, "bucket", bytepool.AutoBucket.String())
+}
@@ -0,0 +1,70 @@
+package bytepool
+
+// As GetGrown with cap at most maxCap. When the bucket for want is larger than maxCap, Bytes of
+// cap want are allocated and discarded on release, counted as Capped. Panics if want > maxCap,
+// or with NoPanics treats maxCap as want.
+func (p *BucketPool) GetGrownAtMost(want, maxCap int) *Bytes {
+	if want > maxCap {
+		if !p.misused("want > maxCap") {
+			panic("want > maxCap")
+		}
+		maxCap = want
+	}
+	idx, sp := p.findPool(want)
+	if sp == nil {
+		return p.GetGrown(want)
+	}
+	if sp.size <= maxCap {
+		return p.get(idx, sp, maxCap)
+	}
+	entry := p.overGate.enter()
+	p.capped.Add(1)
+	p.overGate.exit(entry)
+
+	b := p.makeOver(want)
+	b.pool = discarder{p}
+	return b
+}
+
+func (g *BucketPooler) GetGrownAtMost(want, maxCap int) *Bytes {
+	return g.pool.GetGrownAtMost(want, maxCap)
+}
+
+// Bytes with cap between minCap and maxCap, preferring one retained by any bucket in the range,
+// smallest first, before allocating as GetGrownAtMost(minCap, maxCap). Panics if minCap > maxCap,
+// or with NoPanics treats maxCap as minCap.
+func (p *BucketPool) GetBetween(minCap, maxCap int) *Bytes {
+	if minCap > maxCap {
+		if !p.misused("minCap > maxCap") {
+			panic("minCap > maxCap")
+		}
+		maxCap = minCap
+	}
+	if idx, _ := p.findPool(minCap); idx >= 0 {
+		for _, sp := range p.pools[idx:] {
+			if sp.size > maxCap {
+				break
+			}
+			if _, bsp := p.findPool(sp.size); bsp != sp {
+				break // bypassed.
+			}
+			if b := sp.getNoAlloc(p); b != nil {
+				return b
+			}
+		}
+	}
+	return p.GetGrownAtMost(minCap, maxCap)
+}
+
+func (g *BucketPooler) GetBetween(minCap, maxCap int) *Bytes {
+	return g.pool.GetBetween(minCap, maxCap)
+}
+
+// Origin of Bytes no bucket should retain, such as undersized for their bucket.
+type discarder struct {
+	pool *BucketPool
+}
+
+func (d discarder) put(b *Bytes) {
+	d.pool.discard(b)
+}
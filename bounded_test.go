@@ -0,0 +1,59 @@
+package bytepool_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_GetGrownAtMost(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{16, 64}, bytepool.BucketPoolOptions{MaxRetained: 4})
+
+	b := pool.GetGrownAtMost(10, 100)
+	diffFatal(t, 16, cap(b.B))
+	b.Release()
+
+	b = pool.GetGrownAtMost(20, 40)
+	diffFatal(t, [2]int{0, 20}, [2]int{len(b.B), cap(b.B)})
+	b.Release() // discarded, not retained by the 64 bucket.
+
+	b = pool.GetGrownAtMost(100, 200) // over.
+	b.Release()
+
+	s := pool.Stats()
+	diffFatal(t, [3]uint64{1, 2, 2}, [3]uint64{s.Capped, s.Overs, s.Puts}) // overs are gets and puts.
+	diffFatal(t, 1, s.Pooled)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic")
+		}
+	}()
+	pool.GetGrownAtMost(2, 1)
+}
+
+func TestBucket_GetBetween(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{4, 16, 64}, bytepool.BucketPoolOptions{MaxRetained: 4})
+
+	pool.GetGrown(64).Release()
+	b := pool.GetBetween(5, 64) // the retained 64, skipping the empty 16.
+	diffFatal(t, 64, cap(b.B))
+	b.Release()
+
+	b = pool.GetBetween(5, 63) // allocated from 16.
+	diffFatal(t, 16, cap(b.B))
+	b.Release()
+
+	b = pool.GetBetween(17, 63) // allocated exact.
+	diffFatal(t, 17, cap(b.B))
+	b.Release()
+
+	s := pool.Stats()
+	diffFatal(t, [3]uint64{1, 2, 1}, [3]uint64{s.Hits, s.Misses, s.Capped})
+}
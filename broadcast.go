@@ -0,0 +1,288 @@
+package bytepool
+
+import (
+	"container/heap"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"sync"
+)
+
+// Returned by a BroadcastReader's Read once evicted for falling MaxBuffered behind.
+var ErrBroadcastEvicted = errors.New("bytepool: broadcast reader evicted")
+
+// One writer appending into pooled chunks, read by any number of BroadcastReaders each at its
+// own offset, such as to fan out a log tail. A chunk returns to the pool once every reader has
+// passed it, so a stalled reader holds all written since it stalled; Close it to let go, or
+// bound it with MaxBuffered. Write and Close are safe to call concurrently with readers.
+type Broadcast struct {
+	pool        SizedPooler
+	chunkSize   int
+	maxBuffered int64
+	evict       bool
+
+	mu      sync.Mutex
+	cond    sync.Cond
+	chunks  []broadcastChunk // in offset order, from the oldest a reader needs.
+	end     int64            // offset of the next Write.
+	readers readerHeap       // slowest first.
+	closed  bool
+}
+
+type BroadcastOptions struct {
+	ChunkSize int // of Bytes from the pool. Defaults to 4096.
+
+	// Bytes written and not yet read by the slowest reader, over which Write blocks until
+	// readers catch up or are closed. Zero is unlimited.
+	MaxBuffered int64
+
+	// Over MaxBuffered, Write evicts the slowest readers rather than blocking, their Reads
+	// returning ErrBroadcastEvicted.
+	EvictSlowest bool
+}
+
+type broadcastChunk struct {
+	start int64 // offset of B[0].
+	b     *Bytes
+}
+
+// Reads a Broadcast from the offset it was made at.
+type BroadcastReader struct {
+	bc *Broadcast
+
+	// under bc.mu.
+	off     int64
+	idx     int // in bc.readers, -1 once closed.
+	evicted bool
+}
+
+// Chunks are Bytes of at least chunkSize from p. Panics if chunkSize < 1.
+func NewBroadcast(p SizedPooler, chunkSize int) *Broadcast {
+	if chunkSize < 1 {
+		panic("chunkSize < 1")
+	}
+	return NewBroadcastOptions(p, BroadcastOptions{ChunkSize: chunkSize})
+}
+
+func NewBroadcastOptions(p SizedPooler, o BroadcastOptions) *Broadcast {
+	if o.ChunkSize <= 0 {
+		o.ChunkSize = 4096
+	}
+	bc := &Broadcast{
+		pool:        p,
+		chunkSize:   o.ChunkSize,
+		maxBuffered: max(0, o.MaxBuffered),
+		evict:       o.EvictSlowest,
+	}
+	bc.cond.L = &bc.mu
+	return bc
+}
+
+// Appends data for readers. Errors with io.ErrClosedPipe after Close, including while blocked
+// over MaxBuffered.
+func (bc *Broadcast) Write(data []byte) (int, error) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	if bc.closed {
+		return 0, io.ErrClosedPipe
+	}
+	var n int
+	for len(data) > 0 {
+		room := bc.room()
+		for room == 0 && !bc.closed {
+			if bc.evict {
+				bc.readers[0].evict()
+			} else {
+				bc.cond.Wait()
+			}
+			room = bc.room()
+		}
+		if bc.closed {
+			return n, io.ErrClosedPipe
+		}
+		if len(bc.chunks) == 0 || bc.chunks[len(bc.chunks)-1].full() {
+			bc.chunks = append(bc.chunks, broadcastChunk{start: bc.end, b: bc.pool.GetGrown(bc.chunkSize)})
+		}
+		c := &bc.chunks[len(bc.chunks)-1]
+		w := int(min(int64(len(data)), int64(cap(c.b.B)-len(c.b.B)), room))
+		c.b.B = append(c.b.B, data[:w]...)
+		bc.end += int64(w)
+		data = data[w:]
+		n += w
+		bc.cond.Broadcast()
+	}
+	bc.recycle()
+	return n, nil
+}
+
+// Bytes writable before MaxBuffered. Must hold mu.
+func (bc *Broadcast) room() int64 {
+	if bc.maxBuffered == 0 {
+		return math.MaxInt64
+	}
+	return max(0, bc.maxBuffered-(bc.end-bc.minOffset()))
+}
+
+// Ends the Broadcast, readers getting io.EOF once they have read all written.
+func (bc *Broadcast) Close() error {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	bc.closed = true
+	bc.recycle()
+	bc.cond.Broadcast()
+	return nil
+}
+
+// A reader from the current end, seeing only later writes.
+func (bc *Broadcast) NewReader() *BroadcastReader {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	r := &BroadcastReader{bc: bc, off: bc.end}
+	heap.Push(&bc.readers, r)
+	return r
+}
+
+// Bytes written that some reader has yet to read.
+func (bc *Broadcast) Buffered() int64 {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	if len(bc.chunks) == 0 {
+		return 0
+	}
+	return bc.end - max(bc.chunks[0].start, bc.minOffset())
+}
+
+func (c broadcastChunk) full() bool {
+	return len(c.b.B) == cap(c.b.B)
+}
+
+// must hold mu.
+func (bc *Broadcast) minOffset() int64 {
+	if len(bc.readers) == 0 {
+		return bc.end
+	}
+	return bc.readers[0].off
+}
+
+// Readers by offset. Must hold mu.
+type readerHeap []*BroadcastReader
+
+func (h readerHeap) Len() int           { return len(h) }
+func (h readerHeap) Less(i, j int) bool { return h[i].off < h[j].off }
+
+func (h readerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].idx = i
+	h[j].idx = j
+}
+
+func (h *readerHeap) Push(x any) {
+	r := x.(*BroadcastReader)
+	r.idx = len(*h)
+	*h = append(*h, r)
+}
+
+func (h *readerHeap) Pop() any {
+	old := *h
+	r := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	r.idx = -1
+	return r
+}
+
+// Releases chunks every reader has passed, keeping a partial chunk for further writes.
+// Must hold mu.
+func (bc *Broadcast) recycle() {
+	low := bc.minOffset()
+	var n int
+	for _, c := range bc.chunks {
+		if c.start+int64(len(c.b.B)) > low || !c.full() && !bc.closed {
+			break
+		}
+		c.b.Release()
+		n++
+	}
+	clear(bc.chunks[:n]) // not holding released Bytes.
+	bc.chunks = bc.chunks[n:]
+}
+
+// Copies written data at the reader's offset into p, blocking until there is some.
+// Errors with io.EOF once the Broadcast is closed and read, or io.ErrClosedPipe after Close.
+func (r *BroadcastReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	bc := r.bc
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	for r.off == bc.end && !bc.closed && r.idx >= 0 {
+		bc.cond.Wait()
+	}
+	if r.evicted {
+		return 0, ErrBroadcastEvicted
+	}
+	if r.idx < 0 {
+		return 0, io.ErrClosedPipe
+	}
+	if r.off == bc.end {
+		return 0, io.EOF
+	}
+
+	i := sort.Search(len(bc.chunks), func(i int) bool {
+		return bc.chunks[i].start+int64(len(bc.chunks[i].b.B)) > r.off
+	})
+	var n int
+	for ; i < len(bc.chunks) && n < len(p); i++ {
+		c := bc.chunks[i]
+		w := copy(p[n:], c.b.B[r.off-c.start:])
+		n += w
+		r.off += int64(w)
+	}
+	heap.Fix(&bc.readers, r.idx)
+	bc.recycle()
+	if bc.maxBuffered > 0 {
+		bc.cond.Broadcast() // a blocked Write.
+	}
+	return n, nil
+}
+
+// Offset of the next Read in the Broadcast's writes.
+func (r *BroadcastReader) Offset() int64 {
+	r.bc.mu.Lock()
+	defer r.bc.mu.Unlock()
+	return r.off
+}
+
+// Detaches the reader, so it no longer holds chunks. Unblocks a pending Read.
+func (r *BroadcastReader) Close() error {
+	bc := r.bc
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	if r.idx < 0 {
+		return nil
+	}
+	r.detach()
+	return nil
+}
+
+// Must hold mu.
+func (r *BroadcastReader) detach() {
+	bc := r.bc
+	heap.Remove(&bc.readers, r.idx)
+	bc.recycle()
+	bc.cond.Broadcast()
+}
+
+// Must hold mu.
+func (r *BroadcastReader) evict() {
+	r.evicted = true
+	r.detach()
+}
@@ -0,0 +1,161 @@
+package bytepool_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBroadcast(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{4}, bytepool.BucketPoolOptions{MaxRetained: 8})
+	bc := bytepool.NewBroadcast(pool, 4)
+
+	bc.Write([]byte("ignored")) // before any reader, recycled once full.
+	diffFatal(t, int64(1), pool.Stats().Outstanding)
+
+	r1, r2 := bc.NewReader(), bc.NewReader()
+	diffFatal(t, int64(7), r1.Offset())
+	bc.Write([]byte("abcdefghij"))
+	diffFatal(t, int64(10), bc.Buffered())
+
+	p := make([]byte, 6)
+	n, err := r1.Read(p)
+	diffFatal(t, "abcdef", string(p[:n]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, int64(4), pool.Stats().Outstanding) // r2 holds all.
+
+	n, _ = r2.Read(p[:2])
+	diffFatal(t, "ab", string(p[:n]))
+	diffFatal(t, int64(3), pool.Stats().Outstanding) // "ign|ored a" passed by both.
+	diffFatal(t, int64(8), bc.Buffered())
+
+	r2.Close()
+	if _, err := r2.Read(p); !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatal(err)
+	}
+	diffFatal(t, int64(2), pool.Stats().Outstanding)
+
+	bc.Close()
+	rest, err := io.ReadAll(r1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, "ghij", string(rest))
+	diffFatal(t, int64(0), pool.Stats().Outstanding)
+	if _, err := bc.Write([]byte("x")); !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatal(err)
+	}
+}
+
+func TestBroadcast_concurrent(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(8, 1024)
+	bc := bytepool.NewBroadcast(pool, 64)
+
+	var want bytes.Buffer
+	for i := range 1000 {
+		want.WriteByte(byte(i))
+	}
+
+	var readers []*bytepool.BroadcastReader
+	for range 4 {
+		readers = append(readers, bc.NewReader())
+	}
+	var wait sync.WaitGroup
+	got := make([][]byte, len(readers))
+	for i, r := range readers {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			got[i], _ = io.ReadAll(r)
+		}()
+	}
+	data := want.Bytes()
+	for len(data) > 0 {
+		w := min(len(data), 7)
+		bc.Write(data[:w])
+		data = data[w:]
+	}
+	bc.Close()
+	wait.Wait()
+
+	for _, g := range got {
+		diffFatal(t, want.Bytes(), g)
+	}
+	s := pool.Stats()
+	diffFatal(t, s.Gets, s.Puts)
+}
+
+func TestBroadcast_maxBufferedBlocks(t *testing.T) {
+	t.Parallel()
+
+	bc := bytepool.NewBroadcastOptions(bytepool.NewSync(), bytepool.BroadcastOptions{ChunkSize: 4, MaxBuffered: 8})
+	r := bc.NewReader()
+
+	wrote := make(chan int)
+	go func() {
+		n, _ := bc.Write([]byte("abcdefghijkl"))
+		wrote <- n
+	}()
+
+	p := make([]byte, 4)
+	var got []byte
+	for len(got) < 12 {
+		n, err := r.Read(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, p[:n]...)
+		if b := bc.Buffered(); b > 8 {
+			t.Fatal(b)
+		}
+	}
+	diffFatal(t, 12, <-wrote)
+	diffFatal(t, "abcdefghijkl", string(got))
+
+	go func() {
+		bc.Write([]byte("0123456789")) // blocked over 8 until closed.
+	}()
+	for bc.Buffered() < 8 {
+		runtime.Gosched()
+	}
+	bc.Close()
+}
+
+func TestBroadcast_evictSlowest(t *testing.T) {
+	t.Parallel()
+
+	bc := bytepool.NewBroadcastOptions(bytepool.NewSync(), bytepool.BroadcastOptions{
+		ChunkSize:    4,
+		MaxBuffered:  8,
+		EvictSlowest: true,
+	})
+	slow, fast := bc.NewReader(), bc.NewReader()
+
+	p := make([]byte, 16)
+	bc.Write([]byte("abcdef"))
+	fast.Read(p)
+	bc.Write([]byte("ghijkl")) // slow at 12 behind.
+	diffFatal(t, int64(6), bc.Buffered())
+
+	if _, err := slow.Read(p); !errors.Is(err, bytepool.ErrBroadcastEvicted) {
+		t.Fatal(err)
+	}
+	n, err := fast.Read(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, "ghijkl", string(p[:n]))
+	diffFatal(t, nil, slow.Close())
+}
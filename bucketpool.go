@@ -1,22 +1,24 @@
 package bytepool
 
 import (
+	"cmp"
+	"log/slog"
 	"math"
+	"math/bits"
+	"runtime/pprof"
 	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // sizes that increase with the power of two.
 // minSize must be >= 1 and maxSize > minSize.
-func Pow2Sizes(minSize, maxSize int) []int {
-	if minSize < 1 {
-		panic("minSize < 1")
+func Pow2Sizes(minSize, maxSize int) SizeSet {
+	if err := checkSizeRange(minSize, maxSize, 1); err != nil {
+		panic(err.Error())
 	}
-	if maxSize <= minSize {
-		panic("maxSize <= minSize")
-	}
-	var sizes []int
+	var sizes SizeSet
 	const multiplier = 2
 	for s := minSize; s < maxSize; s *= multiplier {
 		sizes = append(sizes, s)
@@ -27,17 +29,11 @@ func Pow2Sizes(minSize, maxSize int) []int {
 
 // Distributes sizes linearly over numBuckets.
 // minSize must be >= 0, maxSize > minSize, and numBuckets >= 2.
-func LinearSizes(minSize, maxSize, numBuckets int) []int {
-	if minSize < 0 {
-		panic("minSize < 0")
-	}
-	if maxSize <= minSize {
-		panic("maxSize <= minSize")
+func LinearSizes(minSize, maxSize, numBuckets int) SizeSet {
+	if err := cmp.Or(checkSizeRange(minSize, maxSize, 0), checkNumBuckets(numBuckets)); err != nil {
+		panic(err.Error())
 	}
-	if numBuckets < 2 {
-		panic("numBuckets < 2")
-	}
-	var sizes []int
+	var sizes SizeSet
 	inc := float64(maxSize-minSize) / float64(numBuckets-1)
 	for i := range numBuckets {
 		v := float64(minSize) + float64(i)*inc
@@ -49,17 +45,11 @@ func LinearSizes(minSize, maxSize, numBuckets int) []int {
 
 // Distributes sizes exponentially over numBuckets.
 // minSize must be >= 1, maxSize > minSize, and numBuckets >= 2.
-func ExpoSizes(minSize, maxSize, numBuckets int) []int {
-	if minSize < 1 {
-		panic("minSize < 1")
-	}
-	if maxSize <= minSize {
-		panic("maxSize <= minSize")
+func ExpoSizes(minSize, maxSize, numBuckets int) SizeSet {
+	if err := cmp.Or(checkSizeRange(minSize, maxSize, 1), checkNumBuckets(numBuckets)); err != nil {
+		panic(err.Error())
 	}
-	if numBuckets < 2 {
-		panic("numBuckets < 2")
-	}
-	var sizes []int
+	var sizes SizeSet
 	// size at i = min * (max/min)^(1/(N-1))
 	r := math.Pow(float64(maxSize)/float64(minSize), 1/float64(numBuckets-1))
 	for i := range numBuckets {
@@ -70,12 +60,63 @@ func ExpoSizes(minSize, maxSize, numBuckets int) []int {
 	return sizes
 }
 
+// Rounds each size up to a multiple of quantum, such as 64 or a page size, avoiding awkward
+// sizes like 9 or 373 from the generators. Repeats are removed.
+// sizes must be sorted and quantum >= 1.
+//
+//	sizes := bytepool.RoundSizesUp(bytepool.ExpoSizes(8, 64<<10, 20), 64)
+func RoundSizesUp(sizes []int, quantum int) SizeSet {
+	if quantum < 1 {
+		panic("quantum < 1")
+	}
+	rounded := make(SizeSet, len(sizes))
+	for i, s := range sizes {
+		rounded[i] = (s + quantum - 1) / quantum * quantum
+	}
+	return slices.Compact(rounded)
+}
+
+// Rounds each size up to the Go allocator's size class, so buckets use the memory their
+// allocations take anyway. Repeats are removed. sizes must be sorted.
+func RoundSizesToClasses(sizes []int) SizeSet {
+	rounded := make(SizeSet, len(sizes))
+	for i, s := range sizes {
+		rounded[i] = cap(slices.Grow([]byte(nil), s)) // append rounds to the size class.
+	}
+	return slices.Compact(rounded)
+}
+
 type BucketPool struct {
-	pools     []*sizedPool
-	overs     atomic.Uint64
-	oversLock atomic.Bool
-	getOvers  []int
-	putOvers  []int
+	pools       []*sizedPool
+	overLabels  *pprof.LabelSet
+	acct        Accountant // can be nil.
+	stop        chan struct{}
+	stopOnce    sync.Once
+	overs       counter
+	overPuts    counter       // part of overs.
+	overSizes   [2][3]counter // by get/put then size class.
+	overGate    statsGate
+	oversLock   atomic.Bool
+	getOvers    []int
+	putOvers    []int
+	bypassAbove int // 0 when unset.
+	bypassBelow int // 0 when unset.
+	probeLarger int
+	bypasses    counter
+	bypassPuts  counter // part of bypasses.
+	capped      counter
+	expired     counter // leases.
+	lateRelease counter // of expired leases.
+	onExpired   func(LeaseExpiry)
+	lost        lostCounts     // with GCReport.
+	overWarn    *overWarner    // can be nil.
+	limit       *allocLimiter  // nil when unlimited.
+	mem         *memoryLimiter // nil when unlimited.
+	windows     *statsWindows  // nil without StatsEpoch.
+	warn        *warner        // nil without Logger.
+	noPanics    bool
+	misuses     counter
+	discards    *discardCounts
 }
 
 // Deprecated.
@@ -85,60 +126,471 @@ func NewBucket(minSize, maxSize int) *BucketPool {
 
 // Suitable for variable sized Bytes if max bounds can be chosen.
 // Puts over max size will be allocated directly.
-// Bytes returned by GetGrown and GetFilled will have cap of first size >= c/length.
-// sizes must not be empty and each must be >= 1. Repeats will be removed.
+// Bytes returned by GetGrown and GetFilled will have cap of first size >= c/length,
+// or larger with ProbeLarger.
+// sizes must not be empty and each must be >= 1, such as a SizeSet. Repeats will be removed.
 func NewBucketFull(sizes []int) *BucketPool {
-	if len(sizes) == 0 {
-		panic("empty sizes")
-	}
-	for _, s := range sizes {
-		if s < 1 {
-			panic("size < 1")
-		}
+	return NewBucketOptions(sizes, BucketPoolOptions{})
+}
+
+type BucketPoolOptions struct {
+	Allocator        Allocator // defaults to the Go heap. Not used for overs.
+	AllocatorMinSize int       // buckets with size >= use Allocator. Defaults to all buckets.
+
+	// Splits each bucket by the caller's NUMA node (Linux only) so reused Bytes tend to be node local.
+	// Best effort as goroutines can migrate between Get and Release, and the node is cached per P
+	// for a thousand or so uses. Use the machine's node count.
+	// Defaults to 1.
+	NodeShards int
+
+	// Identifies the pool, such as in profile labels.
+	Name string
+
+	// Applies pprof labels (bytepool=Name, bytepool_size=bucket size or "over") around allocations.
+	// Labels show in CPU and goroutine profiles, heap profiles only record stacks.
+	// As pprof.Do, an allocating goroutine's own labels are cleared afterwards, reapply them
+	// with pprof.SetGoroutineLabels where needed.
+	ProfileLabels bool
+
+	// Notified of allocations and of released Bytes being retained or discarded.
+	Accountant Accountant
+
+	// Limits the Bytes each bucket retains, further puts are dropped. Buckets then use a
+	// free list rather than sync.Pool, so retained Bytes also survive GC and NodeShards is unused.
+	// Defaults to unlimited.
+	MaxRetained int
+
+	// Bytes allocated per bucket at construction into its free list, so steady state Gets and
+	// Releases perform no heap allocations while within the count. Raises MaxRetained to at least this.
+	Preallocate int
+
+	// When MaxRetained is reached, evicts the least recently used Bytes rather than dropping the put.
+	EvictLRU bool
+
+	// Interval of a background worker zeroing retained Bytes, making GetZeroed near free.
+	// Requires MaxRetained. Stop the worker with Close.
+	ZeroIdle time.Duration
+
+	// Interval of a background worker trimming each bucket's retained Bytes that went unused
+	// over the interval (its low watermark), keeping TrimFloor warm.
+	// Requires MaxRetained. Stop the worker with Close.
+	TrimInterval time.Duration
+	TrimFloor    int
+
+	// Called after each GC cycle with how many pooled Bytes survived it versus were lost,
+	// evidence of what sync.Pool clearing costs. Adds a finalizer to each allocated Bytes.
+	// Called from a background worker, stop it with Close.
+	GCReport func(GCCycleStats)
+
+	// Warns when overs within an OverWarnInterval exceed OverWarnThreshold, at most once per
+	// interval so a flood of overs can't spam logs. Interval defaults to 1 minute.
+	OverWarnLogger    *slog.Logger
+	OverWarnInterval  time.Duration
+	OverWarnThreshold int
+
+	// Alerts when hit or over rates stay degraded.
+	Watchdog WatchdogOptions
+
+	// Default of OverWarnLogger, MemoryLimit Logger and Watchdog Logger, turning on their
+	// warnings. Also warns of AllocLimit limiting, GetLeased expiries and misuse. Every kind of
+	// warning, from any of the loggers, is at most once per LogInterval with a count of those
+	// suppressed. LogInterval defaults to 1 minute.
+	Logger      *slog.Logger
+	LogInterval time.Duration
+
+	// Caps the rate of allocations.
+	AllocLimit AllocLimit
+
+	// Soft and hard limits on the bytes of the pool's Bytes. Requires MaxRetained.
+	MemoryLimit MemoryLimit
+
+	// Buckets with size over this are unused: sizes they would serve are allocated directly
+	// and released Bytes of their capacities discarded, as with overs but counted as Bypassed.
+	// For when retaining large Bytes costs more than allocating them. Defaults to no bypass.
+	BypassAbove int
+
+	// Records how long each bucket's allocations take, as BucketStats AllocLatency, such as to
+	// find buckets worth Preallocate. Adds two clock reads per allocation.
+	AllocLatency bool
+
+	// Records the lengths of each bucket's puts relative to its size, as BucketStats Fill,
+	// showing buckets worth splitting.
+	FillStats bool
+
+	// Called when a GetLeased lease expires before its Bytes are released. Called from its
+	// own goroutine. Optional.
+	OnLeaseExpired func(LeaseExpiry)
+
+	// On a miss, how many larger buckets GetGrown and GetFilled try for a retained Bytes before
+	// allocating, so Bytes may have cap beyond the first fit, those with more hits among their
+	// recent gets first. Suits sparse sizes, where a request fits several buckets well. Probes
+	// stop at bypassed buckets, and GetGrownAtMost's maxCap. Defaults to none.
+	ProbeLarger int
+
+	// Interval of a background worker sampling counters, for BucketPoolStats Windows over each
+	// of StatsWindows, which default to the last 1, 5 and 15 minutes. Windows are accurate to
+	// an epoch. Stop the worker with Close.
+	StatsEpoch   time.Duration
+	StatsWindows []time.Duration
+
+	// Misuse that would panic is instead counted as Misuses, warned of with Logger, and served
+	// as best possible, for services that can't tolerate a panic from a pool. Covers
+	// GetGrownAtMost with want > maxCap, GetBetween with minCap > maxCap, a reused Reservation,
+	// Put or Adopt of a view such as from an Arena, and releasing Bytes over a bucket's size
+	// to it. Pools built on a BucketPool, such as
+	// PinnedPool, Arena, FramePool and Shared, still panic on their own misuse. Constructors
+	// panic on invalid arguments, see TryNewBucket and the other Try functions.
+	NoPanics bool
+
+	// Sizes under this are allocated directly and released Bytes with capacities under it
+	// discarded, counted as Bypassed. For tiny Bytes cheaper to allocate than to pool.
+	// Defaults to no bypass.
+	BypassBelow int
+}
+
+// Same as NewBucketFull with options.
+func NewBucketOptions(sizes []int, o BucketPoolOptions) *BucketPool {
+	if err := checkBucket(sizes, o); err != nil {
+		panic(err.Error())
 	}
 
 	sizes = slices.Clone(sizes)
 	slices.Sort(sizes)
 	sizes = slices.Compact(sizes)
+	o.MaxRetained = max(o.MaxRetained, o.Preallocate)
+	if o.Logger != nil {
+		if o.OverWarnLogger == nil {
+			o.OverWarnLogger = o.Logger
+		}
+		if o.MemoryLimit.Logger == nil {
+			o.MemoryLimit.Logger = o.Logger
+		}
+		if o.Watchdog.Logger == nil {
+			o.Watchdog.Logger = o.Logger
+		}
+	}
 
-	var pools []*sizedPool
-	for _, s := range sizes {
-		pools = append(pools, newSizedPool(s))
+	warn := newWarner(o.Logger, o.Name, o.LogInterval)
+	warnerOf := func(logger *slog.Logger) *warner { // sharing suppression with Logger.
+		if logger == o.Logger {
+			return warn
+		}
+		return newWarner(logger, o.Name, o.LogInterval)
 	}
-	return &BucketPool{pools: pools}
+
+	p := &BucketPool{
+		overLabels:  allocLabels(o.ProfileLabels, o.Name, "over"),
+		acct:        o.Accountant,
+		stop:        make(chan struct{}),
+		limit:       newAllocLimiter(o.AllocLimit),
+		mem:         newMemoryLimiter(o.MemoryLimit, o.MaxRetained, warnerOf(o.MemoryLimit.Logger)),
+		bypassAbove: max(o.BypassAbove, 0),
+		bypassBelow: max(o.BypassBelow, 0),
+		onExpired:   o.OnLeaseExpired,
+		warn:        warn,
+		probeLarger: max(o.ProbeLarger, 0),
+		noPanics:    o.NoPanics,
+		discards:    new(discardCounts),
+	}
+	if p.limit != nil {
+		p.limit.warn = p.warn
+	}
+	var bucketMems []*memoryLimiter
+	if p.mem != nil {
+		bucketMems = p.mem.buckets(o.MemoryLimit, sizes)
+	}
+	for i, s := range sizes {
+		sp := newSizedPool(s, o.NodeShards)
+		sp.limit = p.limit
+		if bucketMems != nil {
+			sp.mem = bucketMems[i]
+		}
+		if s >= o.AllocatorMinSize {
+			sp.alloc = o.Allocator
+		}
+		sp.labels = allocLabels(o.ProfileLabels, o.Name, sizeLabel(s))
+		sp.acct = o.Accountant
+		sp.discards = p.discards
+		if o.ProbeLarger > 0 {
+			sp.recent = new(atomic.Uint32)
+		}
+		if o.NoPanics {
+			sp.misused = p.misused
+		}
+		if o.AllocLatency {
+			sp.latency = new(latencyHisto)
+		}
+		if o.FillStats {
+			sp.fill = new([4]counter)
+		}
+		if o.MaxRetained > 0 {
+			sp.list = newFreeList(o.MaxRetained, o.EvictLRU)
+		}
+		if o.GCReport != nil {
+			sp.lost = &p.lost
+		}
+		for range o.Preallocate {
+			if p.acct != nil {
+				p.acct.Allocated(s)
+				p.acct.Retained(s)
+			}
+			b := allocSizedBytes(sp.alloc, s, p, sp.lost)
+			b.lost = lostDropped // once out of the list.
+			countMem(b, sp.mem, s)
+			sp.list.put(b)
+		}
+		p.pools = append(p.pools, sp)
+	}
+	if o.ZeroIdle > 0 && o.MaxRetained > 0 {
+		go runEvery(o.ZeroIdle, p.stop, p.zeroIdle)
+	}
+	if o.TrimInterval > 0 && o.MaxRetained > 0 {
+		floor := max(0, o.TrimFloor)
+		go runEvery(o.TrimInterval, p.stop, func() { p.trim(floor) })
+	}
+	if o.GCReport != nil {
+		p.reportGC(o.GCReport)
+	}
+	if o.OverWarnLogger != nil {
+		if o.OverWarnInterval <= 0 {
+			o.OverWarnInterval = time.Minute
+		}
+		p.overWarn = &overWarner{
+			warn:      warnerOf(o.OverWarnLogger),
+			interval:  o.OverWarnInterval,
+			threshold: max(0, o.OverWarnThreshold),
+		}
+	}
+	if o.StatsEpoch > 0 {
+		p.windows = newStatsWindows(o.StatsEpoch, o.StatsWindows)
+		go runEvery(o.StatsEpoch, p.stop, func() { p.windows.record(time.Now(), p.Stats()) })
+	}
+	if o.Watchdog.OnDegraded != nil || o.Watchdog.Logger != nil {
+		w := newWatchdog(o.Watchdog, o.Name, warnerOf(o.Watchdog.Logger))
+		go runEvery(w.o.Interval, p.stop, func() { w.check(p.Stats()) })
+	}
+	return p
+}
+
+// Sizes of the buckets, the bins of a Chooser. Available before any traffic.
+// The result is a copy.
+func (p *BucketPool) Sizes() SizeSet {
+	sizes := make(SizeSet, len(p.pools))
+	for i, sp := range p.pools {
+		sizes[i] = sp.size
+	}
+	return sizes
+}
+
+func (p *BucketPool) bucketMax() int {
+	return p.pools[len(p.pools)-1].size
+}
+
+func (p *BucketPool) bucketFit(c int) int {
+	if _, sp := p.findPool(c); sp != nil {
+		return sp.size
+	}
+	return c
+}
+
+// Stops background workers. The pool remains usable.
+func (p *BucketPool) Close() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
 }
 
 func (p *BucketPool) GetGrown(c int) *Bytes {
-	_, sp := p.findPool(c)
+	idx, sp := p.findPool(c)
 	if sp == nil {
 		p.over(c, false)
-		return makeSizedBytes(c, p)
+		return p.makeOver(c)
 	}
-	return sp.get(p)
+	return p.get(idx, sp, math.MaxInt)
 }
 
 func (p *BucketPool) GetFilled(length int) *Bytes {
-	_, sp := p.findPool(length)
+	idx, sp := p.findPool(length)
 
 	var b *Bytes
 	if sp == nil {
 		p.over(length, false)
-		b = makeSizedBytes(length, p)
+		b = p.makeOver(length)
 	} else {
-		b = sp.get(p)
+		b = p.get(idx, sp, math.MaxInt)
 	}
 	b.B = b.B[:length]
 	return b
 }
 
+// Retained Bytes with zero length and minimum capacity c, or nil rather than allocating.
+// Nil for sizes over the largest bucket.
+func (p *BucketPool) GetRetained(c int) *Bytes {
+	_, sp := p.findPool(c)
+	if sp == nil {
+		return nil
+	}
+	return sp.getNoAlloc(p)
+}
+
+// Bytes with length, all zero.
+// Call Release on the returned Bytes to return it to the pool.
+func (p *BucketPool) GetZeroed(length int) *Bytes {
+	b := p.GetFilled(length)
+	if !b.zeroed {
+		clear(b.B)
+	}
+	return b
+}
+
+// From bucket idx, sp, or with ProbeLarger a retained larger one up to maxCap before allocating.
+func (p *BucketPool) get(idx int, sp *sizedPool, maxCap int) *Bytes {
+	if p.probeLarger == 0 {
+		return sp.get(p)
+	}
+	if b := sp.getNoAlloc(p); b != nil {
+		return b
+	}
+	if b := p.getLarger(idx, maxCap); b != nil {
+		return b
+	}
+	return sp.allocate(p)
+}
+
+// A retained Bytes from the ProbeLarger buckets after idx up to maxCap, nil when none.
+// Buckets with more hits among their recent gets are tried first.
+func (p *BucketPool) getLarger(idx, maxCap int) *Bytes {
+	var probes []*sizedPool
+	for _, sp := range p.pools[idx+1 : min(len(p.pools), idx+1+p.probeLarger)] {
+		if _, fit := p.findPool(sp.size); fit != sp || sp.size > maxCap {
+			break // bypassed or too large, as are those after.
+		}
+		probes = append(probes, sp)
+	}
+	slices.SortStableFunc(probes, func(a, b *sizedPool) int {
+		return cmp.Compare(b.recentHits(), a.recentHits())
+	})
+	for _, sp := range probes {
+		if b := sp.getNoAlloc(p); b != nil {
+			return b
+		}
+	}
+	return nil
+}
+
+// Bytes with length, all v, such as for padding.
+func (p *BucketPool) GetRepeated(length int, v byte) *Bytes {
+	if v == 0 {
+		return p.GetZeroed(length)
+	}
+	b := p.GetFilled(length)
+	fillRepeated(b.B, v)
+	return b
+}
+
+// Doubling copies, as a loop storing v isn't vectorized like clear.
+func fillRepeated(b []byte, v byte) {
+	if len(b) == 0 {
+		return
+	}
+	b[0] = v
+	for n := 1; n < len(b); n *= 2 {
+		copy(b[n:], b[:n])
+	}
+}
+
+func (p *BucketPool) zeroIdle() {
+	for _, sp := range p.pools {
+		for b := sp.list.takeUnzeroed(); b != nil; b = sp.list.takeUnzeroed() {
+			clear(b.B[:cap(b.B)])
+			b.zeroed = true
+			if d := sp.list.putOldest(b); d != nil {
+				p.trimmed(sp, []*Bytes{d}) // filled while zeroing.
+			}
+			select {
+			case <-p.stop:
+				return
+			default:
+			}
+		}
+	}
+}
+
+func (p *BucketPool) trim(floor int) {
+	for _, sp := range p.pools {
+		p.trimmed(sp, sp.list.trim(floor))
+	}
+}
+
+// Accounts for Bytes trimmed from sp's free list.
+func (p *BucketPool) trimmed(sp *sizedPool, trimmed []*Bytes) {
+	for _, b := range trimmed {
+		countMem(b, nil, 0)
+		b.lost = lostDropped
+	}
+	p.discards.add(DiscardTrim, len(trimmed), len(trimmed)*sp.size)
+	entry := sp.gate.enter()
+	sp.trimmed.Add(uint64(len(trimmed)))
+	sp.gate.exit(entry)
+	if p.acct != nil {
+		for _, b := range trimmed {
+			p.acct.Discarded(cap(b.B))
+		}
+	}
+}
+
 type BucketPoolerOptions struct {
 	ChooseInc   int     // defaults to 1k puts.
 	Decay       float64 // defaults to 0.5 (half previous put count).
 	MaxPoolPuts int     // defaults to 100 times ChooseInc.
 	BinChecks   int     // defaults to chosen bin plus 3 ahead. Use 1 to turn off lookahead.
+
+	// Decays put counts of the default Chooser each choice. Defaults to MultiplicativeDecay(Decay).
+	Decayer Decayer
+
+	// Selects the default bin every ChooseInc puts. Defaults to the bin with the most puts,
+	// decayed by Decayer and capped by MaxPoolPuts.
+	Chooser Chooser
+
+	// Consulted by Get and GetFor before the put histogram default, for callers that know the
+	// size ahead of reading. Optional.
+	Predictor SizePredictor
+
+	// Called when the default size changes, by a choice or SetDefaultSize, such as to correlate
+	// latency shifts with calibration. Called from the Release making the choice, so must not
+	// block. Optional.
+	OnDefaultSizeChange func(DefaultSizeChange)
 }
 
-func (p *BucketPool) Pooler(o BucketPoolerOptions) *BucketPooler {
+type DefaultSizeChange struct {
+	Old   int
+	New   int
+	Stats BucketPoolerStats // just after the change, its Calibration showing the cause.
+}
+
+// Selects the default bin of a BucketPooler from the sizes of its Releases.
+// Bins are indexes into the BucketPool's Sizes. Methods are called concurrently.
+type Chooser interface {
+	// Records a Release with a length fitting bin.
+	ObservePut(bin int)
+
+	// Returns the default bin, called every ChooseInc puts.
+	Choose() int
+}
+
+// Predicts the capacity a Get will need by the caller's key, such as a request type.
+type SizePredictor interface {
+	// key is from GetFor, empty from Get. Returns <= 0 to use the put histogram default.
+	PredictSize(key string) int
+}
+
+type SizePredictorFunc func(key string) int
+
+func (f SizePredictorFunc) PredictSize(key string) int {
+	return f(key)
+}
+
+func poolerDefaults(o BucketPoolerOptions) BucketPoolerOptions {
 	if o.ChooseInc <= 0 {
 		o.ChooseInc = 1000
 	}
@@ -152,6 +604,14 @@ func (p *BucketPool) Pooler(o BucketPoolerOptions) *BucketPooler {
 		o.BinChecks = 4
 	}
 	o.BinChecks = max(1, o.BinChecks)
+	if o.Decayer == nil {
+		o.Decayer = MultiplicativeDecay(o.Decay)
+	}
+	return o
+}
+
+func (p *BucketPool) Pooler(o BucketPoolerOptions) *BucketPooler {
+	o = poolerDefaults(o)
 
 	// since pools and bins are not separate and the ranges in sizes can be non-linear, it might
 	// push the default pool up or down. However separating bins out bins to linear can lead to
@@ -159,20 +619,42 @@ func (p *BucketPool) Pooler(o BucketPoolerOptions) *BucketPooler {
 
 	var bins []*histoBin
 	for range p.pools {
-		bins = append(bins, &histoBin{})
+		// covering any BinChecks, as ApplyOptions can raise it.
+		bins = append(bins, &histoBin{hitOffsets: make([]counter, max(o.BinChecks, len(p.pools)))})
+	}
+	if o.Chooser == nil {
+		c := &decayChooser{bins: bins}
+		c.apply(o)
+		c.lastDecay.Store(time.Now().UnixNano())
+		o.Chooser = c
 	}
 	pooler := &BucketPooler{
-		pool:        p,
-		bins:        bins,
-		chooseInc:   int64(o.ChooseInc),
-		decay:       o.Decay,
-		maxPoolPuts: int64(o.MaxPoolPuts),
-		binChecks:   o.BinChecks,
+		pool:      p,
+		bins:      bins,
+		predictor: o.Predictor,
+		chooser:   o.Chooser,
+		onChange:  o.OnDefaultSizeChange,
 	}
+	pooler.chooseInc.Store(int64(o.ChooseInc))
+	pooler.binChecks.Store(int64(o.BinChecks))
 	pooler.puts.Store(-9)
+	pooler.prevIdx.Store(-1)
 	return pooler
 }
 
+func (p *BucketPool) Put(b *Bytes) {
+	if b != nil {
+		adopt(b, p)
+		b.Release()
+	}
+}
+
+func (p *BucketPool) Adopt(b *Bytes) {
+	if b != nil {
+		adopt(b, p)
+	}
+}
+
 func (p *BucketPool) put(b *Bytes) {
 	if b == nil {
 		return
@@ -181,15 +663,61 @@ func (p *BucketPool) put(b *Bytes) {
 	_, pool := p.findPool(cap(b.B))
 	if pool == nil {
 		p.over(cap(b.B), true)
+		p.discard(b)
 		return
 	}
 	pool.put(b)
 }
 
+// Accounts for a released b not retained by any bucket.
+func (p *BucketPool) discard(b *Bytes) {
+	countMem(b, nil, 0)
+	p.discards.add(DiscardOver, 1, cap(b.B))
+	if p.acct != nil {
+		p.acct.Discarded(cap(b.B))
+	}
+}
+
+// Release of sp sized b not retained by a wrapping pool, such as a Tenant over its caps.
+func (p *BucketPool) dropped(sp *sizedPool, b *Bytes) {
+	countMem(b, nil, 0)
+	b.lost = lostDropped
+	p.discards.add(DiscardFull, 1, cap(b.B))
+	entry := sp.gate.enter()
+	sp.puts.Add(1)
+	sp.out.Add(-1)
+	sp.drops.Add(1)
+	sp.gate.exit(entry)
+	if p.acct != nil {
+		p.acct.Discarded(cap(b.B))
+	}
+}
+
 type BucketStats struct {
 	Size   int
+	Gets   uint64 // Hits plus Misses.
+	Puts   uint64
 	Hits   uint64
 	Misses uint64
+	Drops  uint64 // puts not retained or evicted due to MaxRetained, or dropped over MemoryLimit Hard.
+
+	// Gauges, kept by ResetStats. A climbing Outstanding suggests a leak.
+	Outstanding int64 // Bytes got and not yet put. Adopted Bytes, or Bytes grown into another bucket, skew it.
+	Pooled      int   // as BucketContents.
+
+	SavedBytes uint64 // estimated allocation avoided by reuse, Hits times Size.
+
+	Memory int64 // with MemoryLimit Weights, as BucketPoolStats.
+
+	// With TrimInterval, Trimmed also counts MemoryLimit Soft evictions.
+	Trimmed   uint64
+	LowWater  int // retained Bytes range since last trim.
+	HighWater int
+
+	AllocLatency AllocLatencyStats // with AllocLatency.
+
+	// With FillStats, puts by len as quarters of Size: up to 25%, 50%, 75% and 100%.
+	Fill [4]uint64
 }
 
 type BucketPoolStats struct {
@@ -197,54 +725,360 @@ type BucketPoolStats struct {
 	MinSize  int
 	MaxSize  int
 	Sizes    int
+	Gets     uint64 // including overs.
+	Puts     uint64 // including overs.
 	Hits     uint64
 	Misses   uint64
 	Overs    uint64
+	Drops    uint64
+	Trimmed  uint64
 	GetOvers []int
 	PutOvers []int
+
+	// Estimated allocations avoided by reuse, Hits and their bucket sizes.
+	SavedAllocs uint64
+	SavedBytes  uint64
+
+	Limited uint64 // allocations over AllocLimit, whether waited, served smaller or refused.
+
+	Bypassed uint64 // gets and puts under BypassBelow or over BypassAbove within MaxSize, not in Overs.
+
+	Capped uint64 // GetGrownAtMost allocations as the bucket exceeded maxCap.
+
+	Misuses uint64 // with NoPanics, calls that would have panicked.
+
+	// GetLeased leases expired, and their Bytes released after expiring.
+	LeasesExpired uint64
+	LateReleases  uint64
+
+	// With MemoryLimit.
+	Memory    int64 // bytes of Bytes outstanding or retained.
+	SoftTrims uint64
+	HardDrops uint64
+
+	// Sums of the bucket gauges, excluding overs.
+	Outstanding int64
+	Pooled      int
+
+	GetOverSizes OverSizeStats
+	PutOverSizes OverSizeStats
+
+	Windows []WindowStats // by StatsWindows, with StatsEpoch.
+}
+
+// Overs by size relative to MaxSize. Marginal overs suggest another bucket,
+// far overs suggest fixing the caller.
+type OverSizeStats struct {
+	Within2x uint64
+	Within4x uint64
+	Beyond4x uint64
 }
 
 func (p *BucketPool) Stats() BucketPoolStats {
+	if !statsEnabled {
+		return BucketPoolStats{}
+	}
+	for p.oversLock.Swap(true) { // busy loop until not locked
+	}
+	defer p.oversLock.Store(false)
+
+	// all gates for one snapshot, updates only enter one gate at a time.
+	gates := []*statsGate{&p.overGate}
+	for _, sp := range p.pools {
+		gates = append(gates, &sp.gate)
+	}
+	var ps BucketPoolStats
+	readGates(func() {
+		ps = BucketPoolStats{
+			MinSize:  p.pools[0].size,
+			MaxSize:  p.pools[len(p.pools)-1].size,
+			Sizes:    len(p.pools),
+			Overs:    p.overs.Load(),
+			GetOvers: slices.Clone(p.getOvers),
+			PutOvers: slices.Clone(p.putOvers),
+		}
+		if p.limit != nil {
+			ps.Limited = p.limit.limited.Load()
+		}
+		if p.mem != nil {
+			ps.Memory = p.mem.bytes.Load()
+			ps.SoftTrims = p.mem.softTrims.Load()
+			ps.HardDrops = p.mem.hardDrops.Load()
+		}
+		overPuts := p.overPuts.Load()
+		ps.Gets = ps.Overs - overPuts
+		ps.Puts = overPuts
+
+		ps.Capped = p.capped.Load()
+		ps.Misuses = p.misuses.Load()
+		ps.LeasesExpired = p.expired.Load()
+		ps.LateReleases = p.lateRelease.Load()
+		ps.Bypassed = p.bypasses.Load()
+		bypassPuts := p.bypassPuts.Load()
+		ps.Gets += ps.Bypassed - bypassPuts
+		ps.Puts += bypassPuts
+
+		loadOverSizes := func(c *[3]counter) OverSizeStats {
+			return OverSizeStats{
+				Within2x: c[0].Load(),
+				Within4x: c[1].Load(),
+				Beyond4x: c[2].Load(),
+			}
+		}
+		ps.GetOverSizes = loadOverSizes(&p.overSizes[0])
+		ps.PutOverSizes = loadOverSizes(&p.overSizes[1])
+
+		for _, sp := range p.pools {
+			s := BucketStats{
+				Size:    sp.size,
+				Puts:    sp.puts.Load(),
+				Hits:    sp.hits.Load(),
+				Misses:  sp.misses.Load(),
+				Drops:   sp.drops.Load(),
+				Trimmed: sp.trimmed.Load(),
+			}
+			s.Outstanding = sp.out.Load()
+			if sp.latency != nil {
+				s.AllocLatency = sp.latency.stats()
+			}
+			if sp.fill != nil {
+				for i := range sp.fill {
+					s.Fill[i] = sp.fill[i].Load()
+				}
+			}
+			if sp.mem != nil && sp.mem.parent != nil {
+				s.Memory = sp.mem.bytes.Load()
+			}
+			s.Pooled = sp.contents().Pooled
+			if sp.list != nil {
+				s.LowWater, s.HighWater = sp.list.watermarks()
+			}
+			s.Gets = s.Hits + s.Misses
+			s.SavedBytes = s.Hits * uint64(s.Size)
+			ps.Outstanding += s.Outstanding
+			ps.Pooled += s.Pooled
+			if s.Gets <= 0 && s.Puts <= 0 && s.Drops <= 0 && s.Trimmed <= 0 && s.HighWater <= 0 && s.Outstanding == 0 && s.Pooled <= 0 {
+				continue
+			}
+			ps.Gets += s.Gets
+			ps.Puts += s.Puts
+			ps.Hits += s.Hits
+			ps.Misses += s.Misses
+			ps.Drops += s.Drops
+			ps.Trimmed += s.Trimmed
+			ps.SavedBytes += s.SavedBytes
+			ps.Buckets = append(ps.Buckets, s)
+		}
+	}, gates...)
+	ps.SavedAllocs = ps.Hits
+	if p.windows != nil {
+		ps.Windows = p.windows.stats(time.Now(), ps)
+	}
+	return ps
+}
+
+// Zeroes counters, such as to measure from a known point.
+func (p *BucketPool) ResetStats() {
 	for p.oversLock.Swap(true) { // busy loop until not locked
 	}
 	defer p.oversLock.Store(false)
 
-	ps := BucketPoolStats{
-		MinSize:  p.pools[0].size,
-		MaxSize:  p.pools[len(p.pools)-1].size,
-		Sizes:    len(p.pools),
-		Overs:    p.overs.Load(),
-		GetOvers: slices.Clone(p.getOvers),
-		PutOvers: slices.Clone(p.putOvers),
+	p.overGate.seal()
+	defer p.overGate.unseal()
+
+	p.overs.Store(0)
+	p.overPuts.Store(0)
+	p.bypasses.Store(0)
+	p.capped.Store(0)
+	p.misuses.Store(0)
+	p.expired.Store(0)
+	p.lateRelease.Store(0)
+	p.bypassPuts.Store(0)
+	for i := range p.overSizes {
+		for j := range p.overSizes[i] {
+			p.overSizes[i][j].Store(0)
+		}
+	}
+	p.getOvers = nil
+	p.putOvers = nil
+	if p.limit != nil {
+		p.limit.limited.Store(0)
+	}
+	if p.mem != nil { // Memory is a gauge.
+		p.mem.softTrims.Store(0)
+		p.mem.hardDrops.Store(0)
+	}
+
+	for _, sp := range p.pools {
+		sp.gate.seal()
+		sp.puts.Store(0)
+		sp.hits.Store(0)
+		sp.misses.Store(0)
+		sp.drops.Store(0)
+		sp.trimmed.Store(0)
+		if sp.latency != nil {
+			sp.latency.reset()
+		}
+		if sp.fill != nil {
+			for i := range sp.fill {
+				sp.fill[i].Store(0)
+			}
+		}
+		sp.gate.unseal()
 	}
+}
+
+// Discards every retained Bytes, such as to release memory during an incident.
+// Returns how many were discarded.
+func (p *BucketPool) Drain() int {
+	var n int
 	for _, sp := range p.pools {
-		s := BucketStats{
-			Size:   sp.size,
-			Hits:   sp.hits.Load(),
-			Misses: sp.misses.Load(),
+		drained := sp.drain()
+		n += len(drained)
+		for _, b := range drained {
+			countMem(b, nil, 0)
+			b.lost = lostDropped
+		}
+		p.discards.add(DiscardTrim, len(drained), len(drained)*sp.size)
+		if p.acct != nil {
+			for _, b := range drained {
+				p.acct.Discarded(cap(b.B))
+			}
+		}
+	}
+	return n
+}
+
+type BucketContents struct {
+	Size   int
+	Pooled int  // Bytes currently retained.
+	Exact  bool // false for sync.Pool backed buckets, where Pooled is unknown and 0 as GC drops are silent.
+}
+
+// Reports what each bucket currently retains, in size order.
+func (p *BucketPool) Inspect() []BucketContents {
+	contents := make([]BucketContents, len(p.pools))
+	for i, sp := range p.pools {
+		contents[i] = sp.contents()
+	}
+	return contents
+}
+
+// Returns the memory of retained Bytes to the OS when the Allocator is an OSMemoryReleaser,
+// such as HugePageAllocator, similar to debug.FreeOSMemory but for this pool. The Bytes stay
+// retained and read as zero. Returns the capacity released.
+func (p *BucketPool) ReleaseOSMemory() int {
+	var released int
+	for _, sp := range p.pools {
+		r, ok := sp.alloc.(OSMemoryReleaser)
+		if !ok {
+			continue
+		}
+		release := func(b *Bytes) {
+			if r.ReleaseOSMemory(b.B) {
+				released += cap(b.B)
+				b.zeroed = true
+			}
 		}
-		if s.Hits <= 0 && s.Misses <= 0 {
+		if sp.list != nil {
+			sp.list.each(release)
 			continue
 		}
-		ps.Hits += s.Hits
-		ps.Misses += s.Misses
-		ps.Buckets = append(ps.Buckets, s)
+		for _, b := range sp.drain() { // sync.Pool can't be iterated.
+			release(b)
+			sp.syncPool().Put(b)
+		}
 	}
-	return ps
+	return released
+}
+
+func (p *BucketPool) makeOver(c int) *Bytes {
+	p.limit.admit()
+	return p.makeOverAdmitted(c)
+}
+
+func (p *BucketPool) makeOverAdmitted(c int) *Bytes {
+	b := p.makeOverUncounted(c)
+	countMem(b, p.mem, c)
+	return b
+}
+
+// Not counted against MemoryLimit.
+func (p *BucketPool) makeOverUncounted(c int) *Bytes {
+	if p.acct != nil {
+		p.acct.Allocated(c)
+	}
+	var b *Bytes
+	if p.overLabels == nil {
+		b = makeSizedBytes(c, p)
+	} else {
+		b = labeledAlloc(*p.overLabels, func() *Bytes {
+			return makeSizedBytes(c, p)
+		})
+	}
+	return b
 }
 
 // -1/nil when not found.
 func (p *BucketPool) findPool(size int) (idx int, _ *sizedPool) {
+	if size < p.bypassBelow {
+		return -1, nil
+	}
 	for i, sp := range p.pools {
 		if size <= sp.size {
+			if p.bypassAbove > 0 && sp.size > p.bypassAbove {
+				break
+			}
 			return i, sp
 		}
 	}
 	return -1, nil
 }
 
+// Whether findPool found no bucket for size due to BypassBelow or BypassAbove, rather than size being over.
+func (p *BucketPool) bypassed(size int) bool {
+	return size < p.bypassBelow || p.bypassAbove > 0 && size <= p.pools[len(p.pools)-1].size
+}
+
 func (p *BucketPool) over(over int, isPut bool) {
+	var kind, class int
+	if isPut {
+		kind = 1
+	}
+	if p.bypassed(over) {
+		entry := p.overGate.enter()
+		p.bypasses.Add(1)
+		if isPut {
+			p.bypassPuts.Add(1)
+		}
+		p.overGate.exit(entry)
+		return
+	}
+
+	maxSize := p.pools[len(p.pools)-1].size
+	switch {
+	case over <= 2*maxSize:
+		class = 0
+	case over <= 4*maxSize:
+		class = 1
+	default:
+		class = 2
+	}
+
+	entry := p.overGate.enter()
 	p.overs.Add(1)
+	if isPut {
+		p.overPuts.Add(1)
+	}
+	p.overSizes[kind][class].Add(1)
+	p.overGate.exit(entry)
+
+	if p.overWarn != nil {
+		p.overWarn.observe(over, maxSize)
+	}
+	if !statsEnabled {
+		return
+	}
 
 	if p.oversLock.Swap(true) { //  already locked, skip to reduce contention
 		return
@@ -267,23 +1101,36 @@ func (p *BucketPool) over(over int, isPut bool) {
 
 type histoBin struct {
 	puts            atomic.Int64
-	hits            atomic.Uint64
-	hitsLookahead   atomic.Uint64
-	misses          atomic.Uint64
-	missesLookahead atomic.Uint64
+	hits            counter
+	hitsLookahead   counter
+	misses          counter
+	missesLookahead counter
+	hitOffsets      []counter // while default, by offset of the hit bin. Len of at least binChecks.
 }
 
 type BucketPooler struct {
 	// immutable
-	pool        *BucketPool
-	chooseInc   int64
-	maxPoolPuts int64
-	decay       float64
-	binChecks   int
+	pool      *BucketPool
+	predictor SizePredictor
+	chooser   Chooser
+	onChange  func(DefaultSizeChange) // can be nil.
+
+	chooseInc atomic.Int64
+	binChecks atomic.Int64
 
 	bins   []*histoBin // slice immutable, same length as sizes in pool.
+	gate   statsGate   // for bins counters.
 	defIdx atomic.Int64
 	puts   atomic.Int64 // starts at -9
+	pinned atomic.Bool
+	frozen atomic.Bool
+
+	elections    atomic.Uint64
+	lastElection atomic.Int64 // unix nanos, zero when none.
+	lastChange   atomic.Int64 // unix nanos, zero when none.
+	prevIdx      atomic.Int64 // -1 when none.
+
+	predicted counter
 }
 
 func (g *BucketPooler) GetGrown(c int) *Bytes {
@@ -294,10 +1141,35 @@ func (g *BucketPooler) GetFilled(length int) *Bytes {
 	return g.pool.GetFilled(length)
 }
 
+func (g *BucketPooler) GetRepeated(length int, v byte) *Bytes {
+	return g.pool.GetRepeated(length, v)
+}
+
 func (g *BucketPooler) Get() *Bytes {
+	return g.GetFor("")
+}
+
+// As Get, passing key to the Predictor, such as a request type.
+func (g *BucketPooler) GetFor(key string) *Bytes {
 	defIdx := g.defIdx.Load()
 
-	for i := range g.binChecks {
+	if g.predictor != nil {
+		if size := g.predictor.PredictSize(key); size > 0 {
+			entry := g.gate.enter()
+			g.predicted.Add(1)
+			g.gate.exit(entry)
+
+			idx, _ := g.pool.findPool(size)
+			if idx < 0 {
+				b := g.pool.GetGrown(size)
+				b.pool = g
+				return b
+			}
+			defIdx = int64(idx)
+		}
+	}
+
+	for i := range g.binChecks.Load() {
 		idx := defIdx + int64(i)
 		if idx >= int64(len(g.bins)) {
 			break
@@ -308,109 +1180,312 @@ func (g *BucketPooler) Get() *Bytes {
 			continue
 		}
 		bin := g.bins[idx]
+		entry := g.gate.enter()
+		g.bins[defIdx].hitOffsets[i].Add(1)
 		if i > 0 {
 			bin.hitsLookahead.Add(1)
 			g.bins[defIdx].missesLookahead.Add(1)
 		}
 		bin.hits.Add(1)
+		g.gate.exit(entry)
 		return b
 	}
 
 	b := g.pool.pools[defIdx].allocate(g)
+	entry := g.gate.enter()
 	g.bins[defIdx].misses.Add(1)
+	g.gate.exit(entry)
 	return b
 }
 
+func (g *BucketPooler) Put(b *Bytes) {
+	if b != nil {
+		adopt(b, g)
+		b.Release()
+	}
+}
+
+func (g *BucketPooler) Adopt(b *Bytes) {
+	if b != nil {
+		adopt(b, g)
+	}
+}
+
 func (g *BucketPooler) put(b *Bytes) {
 	if b == nil {
 		return
 	}
 
-	defer g.pool.put(b) // after len use below
+	g.observe(len(b.B)) // before put, as b can be taken concurrently once put.
+	g.pool.put(b)
+}
 
-	idx, _ := g.pool.findPool(len(b.B))
+// records a put of length l, choosing the default size every ChooseInc.
+func (g *BucketPooler) observe(l int) {
+	if g.frozen.Load() {
+		return
+	}
+	idx, _ := g.pool.findPool(l)
 	if idx < 0 {
 		return
 	}
 
-	g.bins[idx].puts.Add(1)
+	entry := g.gate.enter()
+	g.chooser.ObservePut(idx)
+	g.gate.exit(entry)
 
 	inc := g.puts.Add(1)
 
 	if inc > 0 {
-		if inc != g.chooseInc {
+		if inc < g.chooseInc.Load() { // rather than !=, as ApplyOptions can lower it.
 			return
 		}
 		defer g.puts.Store(0)
 	} // else ramp from negative for first times.
 
-	g.chooseDefPool()
-	g.reducePuts()
+	if g.pinned.Load() {
+		return
+	}
+	chosen := int64(min(max(0, g.chooser.Choose()), len(g.bins)-1))
+	if g.pinned.Load() { // pinned while choosing.
+		return
+	}
+	now := time.Now().UnixNano()
+	g.elections.Add(1)
+	g.lastElection.Store(now)
+	if prev := g.defIdx.Swap(chosen); prev != chosen {
+		g.prevIdx.Store(prev)
+		g.lastChange.Store(now)
+		g.changed(prev, chosen)
+	}
+}
+
+func (g *BucketPooler) changed(prev, cur int64) {
+	if g.onChange == nil {
+		return
+	}
+	g.onChange(DefaultSizeChange{
+		Old:   g.pool.pools[prev].size,
+		New:   g.pool.pools[cur].size,
+		Stats: g.Stats(),
+	})
+}
+
+// zero Time for zero.
+func unixNanoTime(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// Pins the default to the first bin fitting size, or the largest, suspending automatic
+// selection until UnpinDefaultSize. Puts are still observed, so selection resumes warm.
+func (g *BucketPooler) SetDefaultSize(size int) {
+	idx, _ := g.pool.findPool(size)
+	if idx < 0 {
+		idx = len(g.bins) - 1
+	}
+	g.pinned.Store(true)
+	if prev := g.defIdx.Swap(int64(idx)); prev != int64(idx) {
+		g.changed(prev, int64(idx))
+	}
+}
+
+// Resumes automatic selection from the next choice.
+func (g *BucketPooler) UnpinDefaultSize() {
+	g.pinned.Store(false)
+}
+
+// Stops recording puts and selecting the default, keeping the current one.
+func (g *BucketPooler) Freeze() {
+	g.frozen.Store(true)
+}
+
+func (g *BucketPooler) Unfreeze() {
+	g.frozen.Store(false)
 }
 
 type BinStats struct {
 	Size            int
-	Puts            int64
+	Puts            int64 // decayed counts of the default Chooser.
 	Hits            uint64
 	Misses          uint64
 	HitsLookahead   uint64
 	MissesLookahead uint64
+	HitOffsets      []uint64 // while default, hits by lookahead offset (0 is this bin). Nil when none.
 }
 
 type BucketPoolerStats struct {
 	Bins            []BinStats // only those with positive counters
 	DefaultSize     int
+	Gets            uint64 // Hits plus Misses.
 	Hits            uint64
 	HitsLookahead   uint64
 	Misses          uint64
 	MissesLookahead uint64
+	HitOffsets      []uint64 // hits by lookahead offset from the default bin. Nil when none.
+	Predicted       uint64   // Gets sized by the Predictor.
+	Calibration     CalibrationStats
+}
+
+// How the default came to be, such as to explain a jump in DefaultSize.
+type CalibrationStats struct {
+	Elections           uint64    // default choices, including those keeping the default.
+	LastElection        time.Time // zero when none.
+	LastChange          time.Time // when the default last changed, zero when never.
+	PreviousDefaultSize int       // before LastChange, zero when never.
+	Pinned              bool
+	Frozen              bool
+}
+
+func (g *BucketPooler) defaultSize() int {
+	return g.pool.pools[g.defIdx.Load()].size
+}
+
+// Sizes of the bins, as the pool's. The result is a copy.
+func (g *BucketPooler) Sizes() SizeSet {
+	return g.pool.Sizes()
+}
+
+func (g *BucketPooler) bucketMax() int {
+	return g.pool.bucketMax()
+}
+
+func (g *BucketPooler) bucketFit(c int) int {
+	return g.pool.bucketFit(c)
+}
+
+func (g *BucketPooler) misused(what string) bool {
+	return g.pool.misused(what)
 }
 
 func (g *BucketPooler) Stats() BucketPoolerStats {
-	ps := BucketPoolerStats{
-		DefaultSize: g.pool.pools[g.defIdx.Load()].size,
-	}
-	for i, bin := range g.bins {
-		s := BinStats{
-			Size:            g.pool.pools[i].size,
-			Puts:            bin.puts.Load(),
-			Hits:            bin.hits.Load(),
-			Misses:          bin.misses.Load(),
-			HitsLookahead:   bin.hitsLookahead.Load(),
-			MissesLookahead: bin.missesLookahead.Load(),
-		}
-		if s.Puts <= 0 && s.Hits <= 0 && s.Misses <= 0 && s.HitsLookahead <= 0 && s.MissesLookahead <= 0 {
-			continue
-		}
-		ps.Hits += s.Hits
-		ps.Misses += s.Misses
-		ps.HitsLookahead += s.HitsLookahead
-		ps.MissesLookahead += s.MissesLookahead
-		ps.Bins = append(ps.Bins, s)
+	if !statsEnabled {
+		return BucketPoolerStats{}
 	}
+	var ps BucketPoolerStats
+	readGates(func() {
+		ps = BucketPoolerStats{
+			DefaultSize: g.defaultSize(),
+		}
+		for i, bin := range g.bins {
+			s := BinStats{
+				Size:            g.pool.pools[i].size,
+				Puts:            bin.puts.Load(),
+				Hits:            bin.hits.Load(),
+				Misses:          bin.misses.Load(),
+				HitsLookahead:   bin.hitsLookahead.Load(),
+				MissesLookahead: bin.missesLookahead.Load(),
+			}
+			binChecks := int(g.binChecks.Load())
+			for j := range bin.hitOffsets {
+				if v := bin.hitOffsets[j].Load(); v > 0 {
+					if s.HitOffsets == nil {
+						s.HitOffsets = make([]uint64, binChecks)
+					}
+					if j >= len(s.HitOffsets) { // from before ApplyOptions lowered BinChecks.
+						s.HitOffsets = append(s.HitOffsets, make([]uint64, j+1-len(s.HitOffsets))...)
+					}
+					s.HitOffsets[j] = v
+				}
+			}
+			if s.Puts <= 0 && s.Hits <= 0 && s.Misses <= 0 && s.HitsLookahead <= 0 && s.MissesLookahead <= 0 && s.HitOffsets == nil {
+				continue
+			}
+			if s.HitOffsets != nil {
+				if len(ps.HitOffsets) < len(s.HitOffsets) {
+					ps.HitOffsets = append(ps.HitOffsets, make([]uint64, len(s.HitOffsets)-len(ps.HitOffsets))...)
+				}
+				for j, v := range s.HitOffsets {
+					ps.HitOffsets[j] += v
+				}
+			}
+			ps.Hits += s.Hits
+			ps.Misses += s.Misses
+			ps.HitsLookahead += s.HitsLookahead
+			ps.MissesLookahead += s.MissesLookahead
+			ps.Bins = append(ps.Bins, s)
+		}
+		ps.Gets = ps.Hits + ps.Misses
+		ps.Predicted = g.predicted.Load()
+		ps.Calibration = CalibrationStats{
+			Elections:    g.elections.Load(),
+			LastElection: unixNanoTime(g.lastElection.Load()),
+			LastChange:   unixNanoTime(g.lastChange.Load()),
+			Pinned:       g.pinned.Load(),
+			Frozen:       g.frozen.Load(),
+		}
+		if prev := g.prevIdx.Load(); prev >= 0 {
+			ps.Calibration.PreviousDefaultSize = g.pool.pools[prev].size
+		}
+	}, &g.gate)
 	return ps
 }
 
-func (g *BucketPooler) chooseDefPool() {
+// Zeroes hit and miss counters, puts are kept as they drive the default size.
+func (g *BucketPooler) ResetStats() {
+	g.gate.seal()
+	defer g.gate.unseal()
+
+	g.predicted.Store(0)
+	for _, bin := range g.bins {
+		bin.hits.Store(0)
+		bin.hitsLookahead.Store(0)
+		bin.misses.Store(0)
+		bin.missesLookahead.Store(0)
+		for i := range bin.hitOffsets {
+			bin.hitOffsets[i].Store(0)
+		}
+	}
+}
+
+// Default Chooser, the bin with the most puts. Counts are kept in the histoBins for stats.
+type decayChooser struct {
+	bins        []*histoBin
+	decayer     atomic.Pointer[decayerRef]
+	maxPoolPuts atomic.Int64
+	lastDecay   atomic.Int64 // unix nanos.
+}
+
+type decayerRef struct {
+	Decayer
+}
+
+// o with defaults applied.
+func (c *decayChooser) apply(o BucketPoolerOptions) {
+	c.decayer.Store(&decayerRef{o.Decayer})
+	c.maxPoolPuts.Store(int64(o.MaxPoolPuts))
+}
+
+func (c *decayChooser) ObservePut(bin int) {
+	c.bins[bin].puts.Add(1)
+}
+
+func (c *decayChooser) Choose() int {
 	maxPuts := int64(-1)
 	var bestPool int
 
-	for i, bin := range g.bins {
+	for i, bin := range c.bins {
 		v := bin.puts.Load()
 		if v > maxPuts {
 			maxPuts = v
 			bestPool = i
 		}
 	}
-	g.defIdx.Store(int64(bestPool))
+	c.reducePuts()
+	return bestPool
 }
 
-func (g *BucketPooler) reducePuts() {
-	for _, bin := range g.bins {
+func (c *decayChooser) reducePuts() {
+	now := time.Now().UnixNano()
+	elapsed := time.Duration(max(0, now-c.lastDecay.Swap(now)))
+
+	decayer, maxPoolPuts := c.decayer.Load(), c.maxPoolPuts.Load()
+	for _, bin := range c.bins {
 		for {
 			v := bin.puts.Load()
-			decayed := math.RoundToEven(float64(v) * g.decay)
-			v2 := min(int64(decayed), g.maxPoolPuts)
+			v2 := min(decayer.Decay(v, elapsed), maxPoolPuts)
 			if bin.puts.CompareAndSwap(v, v2) {
 				break
 			}
@@ -419,15 +1494,78 @@ func (g *BucketPooler) reducePuts() {
 }
 
 type sizedPool struct {
-	size int
-	pool sync.Pool
+	size   int
+	pool   sync.Pool
+	shards []sync.Pool    // by NUMA node, pool is unused when set.
+	limit  *allocLimiter  // shared by the BucketPool, nil when unlimited.
+	mem    *memoryLimiter // the BucketPool's or with Weights the bucket's, nil when unlimited.
+	alloc  Allocator      // nil for Go heap.
+	labels *pprof.LabelSet
+	acct   Accountant // can be nil.
+	list   *freeList  // used instead of sync pools when set.
+
+	lost *lostCounts // shared by the BucketPool, set with GCReport.
 
-	hits   atomic.Uint64
-	misses atomic.Uint64
+	misused  func(string) bool // the BucketPool's with NoPanics, else nil.
+	recent   *atomic.Uint32    // with ProbeLarger, the last 32 getNoAllocs as bits, 1 a hit.
+	discards *discardCounts    // shared by the BucketPool.
+
+	puts    counter
+	hits    counter
+	misses  counter
+	drops   counter
+	trimmed counter
+	gate    statsGate
+
+	latency *latencyHisto // nil without AllocLatency.
+	fill    *[4]counter   // nil without FillStats, by quarter of size.
+
+	out gauge
 }
 
-func newSizedPool(size int) *sizedPool {
-	return &sizedPool{size: size}
+func newSizedPool(size, shards int) *sizedPool {
+	p := &sizedPool{size: size}
+	if shards > 1 {
+		p.shards = make([]sync.Pool, shards)
+	}
+	return p
+}
+
+func (p *sizedPool) syncPool() *sync.Pool {
+	if p.shards == nil {
+		return &p.pool
+	}
+	return &p.shards[currentNode()%len(p.shards)]
+}
+
+// removes retained Bytes.
+func (p *sizedPool) drain() []*Bytes {
+	if p.list != nil {
+		return p.list.drain()
+	}
+
+	var drained []*Bytes
+	drainPool := func(sp *sync.Pool) {
+		for {
+			b, _ := sp.Get().(*Bytes)
+			if b == nil {
+				return
+			}
+			drained = append(drained, b)
+		}
+	}
+	drainPool(&p.pool)
+	for i := range p.shards {
+		drainPool(&p.shards[i])
+	}
+	return drained
+}
+
+func (p *sizedPool) contents() BucketContents {
+	if p.list != nil {
+		return BucketContents{Size: p.size, Pooled: p.list.len(), Exact: true}
+	}
+	return BucketContents{Size: p.size} // GC drops from sync.Pool are silent.
 }
 
 // returned bytes will have cap == sp.size.
@@ -439,13 +1577,50 @@ func (p *sizedPool) get(pp poolPutter) *Bytes {
 	return p.allocate(pp)
 }
 
+// Hits among the last 32 getNoAllocs, zero without ProbeLarger.
+func (p *sizedPool) recentHits() int {
+	if p.recent == nil {
+		return 0
+	}
+	return bits.OnesCount32(p.recent.Load())
+}
+
+// Lossy under contention, fine for a hint.
+func (p *sizedPool) record(hit bool) {
+	var bit uint32
+	if hit {
+		bit = 1
+	}
+	p.recent.Store(p.recent.Load()<<1 | bit)
+}
+
 // returns nil if miss.
 func (p *sizedPool) getNoAlloc(pp poolPutter) *Bytes {
-	b, _ := p.pool.Get().(*Bytes)
+	var b *Bytes
+	if p.list != nil {
+		b = p.list.get()
+	} else {
+		b, _ = p.syncPool().Get().(*Bytes)
+	}
+	if p.recent != nil {
+		p.record(b != nil)
+	}
 	if b == nil {
 		return nil
 	}
+	if p.lost != nil {
+		b.lost = lostUnreleased
+	}
+	entry := p.gate.enter()
 	p.hits.Add(1)
+	p.out.Add(1)
+	p.gate.exit(entry)
+	if p.acct != nil {
+		p.acct.Reused(cap(b.B))
+		if cap(b.B) < p.size { // reallocated by Sized
+			p.acct.Allocated(p.size)
+		}
+	}
 	b.B = Sized(b.B, p.size)
 	// BucketPool and BucketPooler can trade Bytes so
 	// need to set pool to ensure Release flows correctly.
@@ -454,24 +1629,124 @@ func (p *sizedPool) getNoAlloc(pp poolPutter) *Bytes {
 }
 
 func (p *sizedPool) allocate(pp poolPutter) *Bytes {
+	p.limit.admit()
+	return p.allocateAdmitted(pp)
+}
+
+func (p *sizedPool) allocateAdmitted(pp poolPutter) *Bytes {
+	b := p.allocateUncounted(pp)
+	countMem(b, p.mem, p.size)
+	return b
+}
+
+// Not counted against MemoryLimit.
+func (p *sizedPool) allocateUncounted(pp poolPutter) *Bytes {
+	entry := p.gate.enter()
 	p.misses.Add(1)
-	return makeSizedBytes(p.size, pp)
+	p.out.Add(1)
+	p.gate.exit(entry)
+	if p.acct != nil {
+		p.acct.Allocated(p.size)
+	}
+	if p.latency != nil {
+		start := time.Now()
+		defer func() { p.latency.observe(time.Since(start)) }()
+	}
+	var b *Bytes
+	if p.labels == nil {
+		b = allocSizedBytes(p.alloc, p.size, pp, p.lost)
+	} else {
+		b = labeledAlloc(*p.labels, func() *Bytes {
+			return allocSizedBytes(p.alloc, p.size, pp, p.lost)
+		})
+	}
+	return b
 }
 
 // b cannot be nil. cap(b) can't be over p.size.
 func (p *sizedPool) put(b *Bytes) {
 	if cap(b.B) > p.size {
-		panic("unexpected cap")
+		if p.misused == nil || !p.misused("unexpected cap") {
+			panic("unexpected cap")
+		}
+		return
+	}
+
+	if p.fill != nil {
+		q := 0
+		if l := len(b.B); l > 0 {
+			q = min(3, (4*l-1)/p.size)
+		}
+		entry := p.gate.enter()
+		p.fill[q].Add(1)
+		p.gate.exit(entry)
 	}
 
 	b.B = b.B[:0]
-	p.pool.Put(b)
+	b.zeroed = false
+	if p.lost != nil {
+		b.lost = lostCleared
+		if p.list != nil {
+			b.lost = lostDropped // lists only lose what they drop.
+		}
+	}
+	countMem(b, p.mem, p.size)
+	size := cap(b.B) // b can be taken concurrently once put.
+
+	var dropped, trimmed *Bytes
+	dropReason := DiscardFull
+	switch {
+	case p.list == nil:
+		p.syncPool().Put(b)
+	case p.mem.overHard():
+		dropped = b
+		dropReason = DiscardBudget
+		p.mem.root().hardDrops.Add(1)
+	default:
+		dropped = p.list.put(b)
+		if dropped == nil && p.mem.overSoft() {
+			trimmed = p.list.evictOldest()
+			p.mem.root().softTrims.Add(1)
+		}
+	}
+
+	entry := p.gate.enter()
+	p.puts.Add(1)
+	p.out.Add(-1)
+	if dropped != nil {
+		p.drops.Add(1)
+	}
+	if trimmed != nil {
+		p.trimmed.Add(1)
+	}
+	p.gate.exit(entry)
+
+	for _, d := range [2]*Bytes{dropped, trimmed} {
+		if d == nil {
+			continue
+		}
+		countMem(d, nil, 0)
+		if d == trimmed {
+			dropReason = DiscardBudget
+		}
+		p.discards.add(dropReason, 1, cap(d.B))
+		if p.acct != nil {
+			p.acct.Discarded(cap(d.B))
+		}
+		if d == b {
+			return
+		}
+	}
+	if p.acct != nil {
+		p.acct.Retained(size)
+	}
 }
 
 // returned bytes have cap c and zero len.
 func makeSizedBytes(c int, p poolPutter) *Bytes {
 	return &Bytes{
-		B:    make([]byte, 0, c),
-		pool: p,
+		B:      make([]byte, 0, c),
+		pool:   p,
+		zeroed: true,
 	}
 }
@@ -9,10 +9,12 @@ import (
 	"sync"
 	"testing"
 	"time"
+	"unsafe"
 
 	"github.com/graxinc/bytepool"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func TestBucket_GetFilled(t *testing.T) {
@@ -88,6 +90,7 @@ func TestBucket_GetGrown(t *testing.T) {
 }
 
 func TestBucket_stats(t *testing.T) {
+	requireStats(t)
 	t.Parallel()
 
 	t.Run("check results", func(t *testing.T) {
@@ -104,19 +107,27 @@ func TestBucket_stats(t *testing.T) {
 			got := pool.Stats()
 			want := bytepool.BucketPoolStats{
 				Buckets: []bytepool.BucketStats{
-					{Size: 2, Hits: 2, Misses: 1},
-					{Size: 4, Hits: 1, Misses: 1},
-					{Size: 8, Hits: 3, Misses: 1},
-					{Size: 9, Misses: 1},
+					{Size: 2, Gets: 3, Puts: 3, Hits: 2, Misses: 1, SavedBytes: 4},
+					{Size: 4, Gets: 2, Puts: 2, Hits: 1, Misses: 1, SavedBytes: 4},
+					{Size: 8, Gets: 4, Puts: 4, Hits: 3, Misses: 1, SavedBytes: 24},
+					{Size: 9, Gets: 1, Puts: 1, Misses: 1},
 				},
 				MinSize:  2,
 				MaxSize:  9,
 				Sizes:    4,
+				Gets:     12,
+				Puts:     12,
 				Hits:     6,
 				Misses:   4,
 				Overs:    4,
 				GetOvers: []int{10, 11},
 				PutOvers: []int{10, 24},
+
+				SavedAllocs: 6,
+				SavedBytes:  32,
+
+				GetOverSizes: bytepool.OverSizeStats{Within2x: 2},
+				PutOverSizes: bytepool.OverSizeStats{Within2x: 1, Within4x: 1},
 			}
 			lastDiff = cmp.Diff(want, got)
 			if lastDiff == "" {
@@ -154,6 +165,7 @@ func TestBucket_stats(t *testing.T) {
 }
 
 func TestBucket_getChoice(t *testing.T) {
+	requireStats(t)
 	t.Parallel()
 
 	cases := []struct {
@@ -167,14 +179,16 @@ func TestBucket_getChoice(t *testing.T) {
 			want: bytepool.BucketPoolerStats{
 				Bins: []bytepool.BinStats{
 					{Size: 2, Misses: 1},
-					{Size: 4, MissesLookahead: 2},
-					{Size: 8, Hits: 4, HitsLookahead: 2},
+					{Size: 4, MissesLookahead: 2, HitOffsets: []uint64{0, 2, 0, 0}},
+					{Size: 8, Hits: 4, HitsLookahead: 2, HitOffsets: []uint64{2, 0, 0, 0}},
 				},
 				DefaultSize:     8,
+				Gets:            5,
 				Hits:            4,
 				Misses:          1,
 				HitsLookahead:   2,
 				MissesLookahead: 2,
+				HitOffsets:      []uint64{2, 2, 0, 0},
 			},
 		},
 		{
@@ -182,15 +196,17 @@ func TestBucket_getChoice(t *testing.T) {
 			chooseInc: 3,
 			want: bytepool.BucketPoolerStats{
 				Bins: []bytepool.BinStats{
-					{Size: 2, Puts: 2, Hits: 2, Misses: 1},
-					{Size: 4, Puts: 1, Hits: 0, Misses: 0, MissesLookahead: 5},
-					{Size: 8, Puts: 1, Hits: 14, Misses: 0, HitsLookahead: 5},
+					{Size: 2, Puts: 2, Hits: 2, Misses: 1, HitOffsets: []uint64{2, 0, 0, 0}},
+					{Size: 4, Puts: 1, Hits: 0, Misses: 0, MissesLookahead: 5, HitOffsets: []uint64{0, 5, 0, 0}},
+					{Size: 8, Puts: 1, Hits: 14, Misses: 0, HitsLookahead: 5, HitOffsets: []uint64{9, 0, 0, 0}},
 				},
 				DefaultSize:     4,
+				Gets:            17,
 				Hits:            16,
 				Misses:          1,
 				HitsLookahead:   5,
 				MissesLookahead: 5,
+				HitOffsets:      []uint64{11, 5, 0, 0},
 			},
 		},
 	}
@@ -207,7 +223,7 @@ func TestBucket_getChoice(t *testing.T) {
 					b.Release()
 				}
 
-				lastDiff = cmp.Diff(c.want, pooler.Stats())
+				lastDiff = cmp.Diff(c.want, pooler.Stats(), ignoreCalibration)
 				if lastDiff == "" {
 					return
 				}
@@ -218,6 +234,7 @@ func TestBucket_getChoice(t *testing.T) {
 }
 
 func TestBucket_getChoice_shared(t *testing.T) {
+	requireStats(t)
 	t.Parallel()
 
 	sizes := bytepool.ExpoSizes(4, 16, 3)
@@ -244,26 +261,30 @@ func TestBucket_getChoice_shared(t *testing.T) {
 			{
 				Bins: []bytepool.BinStats{
 					{Size: 4, Misses: 1},
-					{Size: 8, Hits: 1},
+					{Size: 8, Hits: 1, HitOffsets: []uint64{1, 0, 0, 0}},
 				},
 				DefaultSize: 8,
+				Gets:        2,
 				Hits:        1,
 				Misses:      1,
+				HitOffsets:  []uint64{1, 0, 0, 0},
 			},
 			{
 				Bins: []bytepool.BinStats{
-					{Size: 4, MissesLookahead: 1},
+					{Size: 4, MissesLookahead: 1, HitOffsets: []uint64{0, 1, 0, 0}},
 					{Size: 8, Hits: 1, HitsLookahead: 1},
-					{Size: 16, Hits: 1},
+					{Size: 16, Hits: 1, HitOffsets: []uint64{1, 0, 0, 0}},
 				},
 				DefaultSize:     16,
+				Gets:            2,
 				Hits:            2,
 				HitsLookahead:   1,
 				MissesLookahead: 1,
+				HitOffsets:      []uint64{1, 1, 0, 0},
 			},
 		}
 
-		lastDiff = cmp.Diff(want, got)
+		lastDiff = cmp.Diff(want, got, ignoreCalibration)
 		if lastDiff == "" {
 			return
 		}
@@ -271,7 +292,10 @@ func TestBucket_getChoice_shared(t *testing.T) {
 	t.Fatal(lastDiff)
 }
 
+var ignoreCalibration = cmpopts.IgnoreFields(bytepool.BucketPoolerStats{}, "Calibration")
+
 func TestBucket_getChoice_concurrent(t *testing.T) {
+	requireStats(t)
 	t.Parallel()
 
 	// center 0.5 for n/2.
@@ -491,7 +515,7 @@ func TestBucket_LinearSizes(t *testing.T) {
 
 	cases := []struct {
 		minSize, maxSize, numBuckets int
-		want                         []int
+		want                         bytepool.SizeSet
 	}{
 		{2, 3, 2, []int{2, 3}},
 		{2, 4, 2, []int{2, 4}},
@@ -558,7 +582,7 @@ func TestBucket_ExpoSizes(t *testing.T) {
 
 	cases := []struct {
 		minSize, maxSize, numBuckets int
-		want                         []int
+		want                         bytepool.SizeSet
 	}{
 		{2, 3, 2, []int{2, 3}},
 		{2, 4, 2, []int{2, 4}},
@@ -657,3 +681,795 @@ func BenchmarkBucket_get(b *testing.B) {
 func fillBytes(b *bytepool.Bytes, n int) {
 	b.B = append(b.B, bytes.Repeat([]byte{5}, n)...)
 }
+
+func TestRoundSizesUp(t *testing.T) {
+	t.Parallel()
+
+	diffFatal(t, bytepool.SizeSet{8, 16, 24}, bytepool.RoundSizesUp([]int{1, 8, 9, 15, 16, 17}, 8))
+	diffFatal(t, bytepool.SizeSet{4096, 8192}, bytepool.RoundSizesUp([]int{9, 373, 4097}, 4096))
+	diffFatal(t, bytepool.SizeSet{3, 9}, bytepool.RoundSizesUp([]int{3, 9}, 1))
+	diffFatal(t, bytepool.SizeSet{}, bytepool.RoundSizesUp(nil, 8))
+}
+
+func TestRoundSizesToClasses(t *testing.T) {
+	t.Parallel()
+
+	diffFatal(t, bytepool.SizeSet{8, 16, 48, 384, 8192}, bytepool.RoundSizesToClasses([]int{1, 8, 9, 16, 33, 373, 8000}))
+
+	for _, s := range bytepool.RoundSizesToClasses(bytepool.ExpoSizes(9, 100_000, 30)) {
+		if c := cap(append([]byte(nil), make([]byte, s)...)); c != s { // class sizes round to themselves
+			t.Fatal(s, c)
+		}
+	}
+}
+
+func TestBucket_nodeShards(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{4, 8}, bytepool.BucketPoolOptions{NodeShards: 2})
+
+	var lastDiff string
+	for range 1000 { // can have a buf dropped sometimes
+		b := pool.GetGrown(5)
+		diffFatal(t, 8, cap(b.B))
+		b.Release()
+
+		b = pool.GetFilled(6)
+		diffFatal(t, 6, len(b.B))
+		b.Release()
+
+		s := pool.Stats()
+		lastDiff = cmp.Diff(uint64(1), s.Misses)
+		if lastDiff == "" {
+			return
+		}
+		pool = bytepool.NewBucketOptions([]int{4, 8}, bytepool.BucketPoolOptions{NodeShards: 2})
+	}
+	t.Fatal(lastDiff)
+}
+
+func BenchmarkBucket_nodeShards(b *testing.B) {
+	pool := bytepool.NewBucketOptions([]int{8, 64}, bytepool.BucketPoolOptions{NodeShards: 2})
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			pool.GetGrown(32).Release()
+		}
+	})
+}
+
+func TestBucket_maxRetained(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{4, 8}, bytepool.BucketPoolOptions{MaxRetained: 2})
+
+	var held []*bytepool.Bytes
+	for range 5 {
+		held = append(held, pool.GetGrown(8))
+	}
+	for _, b := range held {
+		b.Release()
+	}
+	for range 3 {
+		pool.GetGrown(8)
+	}
+
+	want := bytepool.BucketPoolStats{
+		Buckets: []bytepool.BucketStats{
+			{Size: 8, Gets: 8, Puts: 5, Hits: 2, Misses: 6, Drops: 3, Outstanding: 3, SavedBytes: 16, HighWater: 2},
+		},
+		MinSize:     4,
+		MaxSize:     8,
+		Sizes:       2,
+		Gets:        8,
+		Puts:        5,
+		Hits:        2,
+		Misses:      6,
+		Drops:       3,
+		SavedAllocs: 2,
+		SavedBytes:  16,
+		Outstanding: 3,
+	}
+	diffFatal(t, want, pool.Stats())
+}
+
+func TestBucket_evictLRU(t *testing.T) {
+	requireStats(t)
+	requireNoDebug(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{MaxRetained: 2, EvictLRU: true})
+
+	var held []*bytepool.Bytes
+	for i := range 4 {
+		b := pool.GetGrown(8)
+		b.B = append(b.B, byte(i))
+		held = append(held, b)
+	}
+	for _, b := range held {
+		b.Release()
+	}
+
+	// most recent first, oldest two evicted.
+	for _, want := range []byte{3, 2} {
+		b := pool.GetFilled(1)
+		diffFatal(t, want, b.B[0])
+	}
+
+	s := pool.Stats()
+	diffFatal(t, uint64(2), s.Drops)
+	diffFatal(t, uint64(2), s.Hits)
+}
+
+// As TestBucket_evictLRU by identity rather than contents, so also with bytepool_debug.
+func TestBucket_evictLRUOrder(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{MaxRetained: 2, EvictLRU: true})
+
+	var held []*bytepool.Bytes
+	var ptrs []*byte
+	for range 4 {
+		b := pool.GetGrown(8)
+		held = append(held, b)
+		ptrs = append(ptrs, unsafe.SliceData(b.B))
+	}
+	for _, b := range held {
+		b.Release()
+	}
+
+	for _, i := range []int{3, 2} { // most recent first.
+		b := pool.GetGrown(8)
+		if unsafe.SliceData(b.B) != ptrs[i] {
+			t.Fatal(i)
+		}
+	}
+	if b := pool.GetGrown(8); unsafe.SliceData(b.B) == ptrs[0] || unsafe.SliceData(b.B) == ptrs[1] {
+		t.Fatal("oldest not evicted")
+	}
+}
+
+func TestBucket_GetRepeated(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(4, 128)
+	for _, v := range []byte{0, ' ', 0xff} {
+		for l := range 100 {
+			b := pool.GetRepeated(l, v)
+			diffFatal(t, bytes.Repeat([]byte{v}, l), b.B, cmpopts.EquateEmpty())
+			fillBytes(b, 10)
+			b.Release()
+		}
+	}
+}
+
+func TestBucket_GetZeroed(t *testing.T) {
+	t.Parallel()
+
+	for _, idle := range []time.Duration{0, time.Millisecond} {
+		t.Run(fmt.Sprint("idle=", idle), func(t *testing.T) {
+			pool := bytepool.NewBucketOptions([]int{4, 8}, bytepool.BucketPoolOptions{MaxRetained: 10, ZeroIdle: idle})
+			defer pool.Close()
+
+			for i := range 100 {
+				b := pool.GetZeroed(1 + i%10)
+				diffFatal(t, make([]byte, 1+i%10), b.B)
+				fillBytes(b, 10)
+				b.B = b.B[:cap(b.B)]
+				b.Release()
+			}
+		})
+	}
+
+	t.Run("worker zeroes", func(t *testing.T) {
+		pool := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{MaxRetained: 10, ZeroIdle: time.Millisecond})
+		defer pool.Close()
+
+		b := pool.GetFilled(8)
+		b.B[3] = 1
+		b.Release()
+
+		timeout := time.Now().Add(10 * time.Second)
+		for time.Now().Before(timeout) {
+			b := pool.GetFilled(8) // doesn't clear
+			zero := b.B[3] == 0
+			b.Release()
+			if zero {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+		t.Fatal("not zeroed")
+	})
+
+	t.Run("worker zeroes all", func(t *testing.T) {
+		pool := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{MaxRetained: 50, ZeroIdle: time.Millisecond})
+		defer pool.Close()
+
+		held := make([]*bytepool.Bytes, 50)
+		for i := range held {
+			held[i] = pool.GetFilled(8)
+			held[i].B[3] = 1
+		}
+		for _, b := range held {
+			b.Release()
+		}
+
+		timeout := time.Now().Add(10 * time.Second)
+		for time.Now().Before(timeout) {
+			zero := true
+			for i := range held {
+				held[i] = pool.GetFilled(8) // doesn't clear
+				zero = zero && held[i].B[3] == 0
+			}
+			for _, b := range held {
+				b.Release()
+			}
+			if zero {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+		t.Fatal("not zeroed")
+	})
+}
+
+func TestBucket_trim(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{
+		MaxRetained:  10,
+		TrimInterval: time.Millisecond,
+		TrimFloor:    1,
+	})
+	defer pool.Close()
+
+	var held []*bytepool.Bytes
+	for range 5 {
+		held = append(held, pool.GetGrown(8))
+	}
+	for _, b := range held {
+		b.Release()
+	}
+
+	timeout := time.Now().Add(10 * time.Second)
+	for pool.Stats().Trimmed < 4 && time.Now().Before(timeout) {
+		time.Sleep(time.Millisecond)
+	}
+	pool.Close()
+
+	s := pool.Stats()
+	diffFatal(t, uint64(4), s.Trimmed)
+	diffFatal(t, 1, s.Buckets[0].HighWater)
+
+	pool.GetGrown(8)
+	diffFatal(t, uint64(1), pool.Stats().Hits)
+}
+
+func TestBucket_overSizes(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketFull([]int{10})
+	for _, n := range []int{11, 20, 21, 40, 41, 1000} {
+		pool.GetGrown(n).Release()
+	}
+	s := pool.Stats()
+	want := bytepool.OverSizeStats{Within2x: 2, Within4x: 2, Beyond4x: 2}
+	diffFatal(t, want, s.GetOverSizes)
+	diffFatal(t, want, s.PutOverSizes)
+}
+
+func TestBucket_statsConsistent(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucket(2, 64)
+	pooler := pool.Pooler(bytepool.BucketPoolerOptions{ChooseInc: 10})
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rando := rand.New(rand.NewPCG(uint64(i), 0))
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				b := pool.GetGrown(rando.IntN(100))
+				b.Release()
+				b = pooler.Get()
+				fillBytes(b, rando.IntN(64))
+				b.Release()
+			}
+		}()
+	}
+
+	// each pair is counted by separate counters updated together.
+	var overs, lookaheads uint64
+	for i := 0; i < 1000 || overs == 0 || lookaheads == 0; i++ {
+		s := pool.Stats()
+		for _, b := range s.Buckets {
+			if b.Outstanding != int64(b.Gets)-int64(b.Puts) {
+				t.Fatal(b)
+			}
+		}
+		o := s.GetOverSizes
+		p := s.PutOverSizes
+		if s.Overs != o.Within2x+o.Within4x+o.Beyond4x+p.Within2x+p.Within4x+p.Beyond4x {
+			t.Fatal(s)
+		}
+
+		ps := pooler.Stats()
+		var offsets uint64
+		for _, v := range ps.HitOffsets {
+			offsets += v
+		}
+		if ps.Hits != offsets || ps.HitsLookahead != ps.MissesLookahead {
+			t.Fatal(ps)
+		}
+		overs, lookaheads = s.Overs, ps.HitsLookahead
+	}
+	close(done)
+	wg.Wait()
+}
+
+// Contended counter updates. Compare -cpu counts, and -tags bytepool_nostats for the ungated baseline.
+func BenchmarkBucket_statsGate(b *testing.B) {
+	pool := bytepool.NewBucket(8, 64)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			pool.GetGrown(32).Release()
+		}
+	})
+}
+
+func TestBucket_resetDrain(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	for _, o := range []bytepool.BucketPoolOptions{{}, {MaxRetained: 10}} {
+		pool := bytepool.NewBucketOptions([]int{8}, o)
+		pooler := pool.Pooler(bytepool.BucketPoolerOptions{})
+
+		b1, b2 := pool.GetGrown(8), pooler.Get()
+		b1.Release()
+		b2.Release()
+		pool.GetGrown(9)
+
+		pool.ResetStats()
+		pooler.ResetStats()
+		s := pool.Stats()
+		diffFatal(t, [5]uint64{}, [5]uint64{s.Gets, s.Puts, s.Hits, s.Misses, s.Overs})
+		ps := pooler.Stats()
+		diffFatal(t, [3]uint64{}, [3]uint64{ps.Gets, ps.Hits, ps.Misses})
+
+		if n := pool.Drain(); o.MaxRetained > 0 && n != 2 {
+			t.Fatal(n)
+		}
+		pool.GetGrown(8)
+		diffFatal(t, uint64(1), pool.Stats().Misses)
+	}
+}
+
+func TestBucket_outstanding(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8, 16}, bytepool.BucketPoolOptions{MaxRetained: 10})
+	pooler := pool.Pooler(bytepool.BucketPoolerOptions{})
+
+	b1, b2, b3 := pool.GetGrown(8), pooler.Get(), pool.GetGrown(16)
+	b1.Release()
+
+	check := func() {
+		t.Helper()
+		s := pool.Stats()
+		diffFatal(t, [2]int64{1, 1}, [2]int64{s.Buckets[0].Outstanding, s.Buckets[1].Outstanding})
+		diffFatal(t, [2]int{1, 0}, [2]int{s.Buckets[0].Pooled, s.Buckets[1].Pooled})
+		diffFatal(t, [2]int64{2, 1}, [2]int64{s.Outstanding, int64(s.Pooled)})
+	}
+	check()
+	pool.ResetStats()
+	check()
+
+	b2.Release()
+	b3.Release()
+	s := pool.Stats()
+	diffFatal(t, [2]int64{0, 3}, [2]int64{s.Outstanding, int64(s.Pooled)})
+}
+
+func TestBucket_Inspect(t *testing.T) {
+	t.Parallel()
+
+	for _, o := range []bytepool.BucketPoolOptions{{}, {MaxRetained: 10}} {
+		exact := o.MaxRetained > 0
+		pool := bytepool.NewBucketOptions([]int{8, 16}, o)
+		diffFatal(t, []bytepool.BucketContents{{Size: 8, Exact: exact}, {Size: 16, Exact: exact}}, pool.Inspect())
+
+		b1, b2, b3 := pool.GetGrown(8), pool.GetGrown(8), pool.GetGrown(16)
+		b1.Release()
+		b2.Release()
+		b3.Release()
+		want := []bytepool.BucketContents{{Size: 8, Exact: exact}, {Size: 16, Exact: exact}}
+		if exact { // sync.Pool buckets are unknown.
+			want[0].Pooled, want[1].Pooled = 2, 1
+		}
+		diffFatal(t, want, pool.Inspect())
+
+		if pool.GetGrown(8); exact {
+			want[0].Pooled = 1
+		}
+		diffFatal(t, want, pool.Inspect())
+
+		pool.Drain()
+		want[0].Pooled, want[1].Pooled = 0, 0
+		diffFatal(t, want, pool.Inspect())
+	}
+}
+
+func TestBucketPooler_predictor(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucket(8, 64)
+	size := 0
+	pooler := pool.Pooler(bytepool.BucketPoolerOptions{
+		Predictor: bytepool.SizePredictorFunc(func(key string) int {
+			if key == "large" {
+				return 60
+			}
+			return size
+		}),
+	})
+
+	b := pooler.Get()
+	diffFatal(t, 8, cap(b.B)) // histogram default
+	b.Release()
+
+	size = 20
+	b = pooler.Get()
+	diffFatal(t, 0, len(b.B))
+	diffFatal(t, 32, cap(b.B))
+	b.Release()
+
+	size = 100
+	b = pooler.Get()
+	diffFatal(t, 0, len(b.B))
+	if cap(b.B) < 100 {
+		t.Fatal(cap(b.B))
+	}
+	b.Release()
+
+	b = pooler.GetFor("large")
+	diffFatal(t, 64, cap(b.B))
+	b.Release()
+
+	diffFatal(t, uint64(3), pooler.Stats().Predicted)
+}
+
+func TestBucket_preallocate(t *testing.T) { // not parallel for AllocsPerRun.
+	pool := bytepool.NewBucketOptions([]int{8, 64}, bytepool.BucketPoolOptions{Preallocate: 4})
+
+	var held [4]*bytepool.Bytes
+	run := func() {
+		for i := range held {
+			held[i] = pool.GetFilled(8 * i)
+		}
+		for _, b := range held {
+			b.Release()
+		}
+	}
+	if allocs := testing.AllocsPerRun(100, run); allocs != 0 {
+		t.Fatal(allocs)
+	}
+	diffFatal(t, uint64(0), pool.Stats().Misses)
+}
+
+type fixedChooser struct {
+	bin      int
+	observed []int
+	mu       sync.Mutex
+}
+
+func (c *fixedChooser) ObservePut(bin int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.observed = append(c.observed, bin)
+}
+
+func (c *fixedChooser) Choose() int {
+	return c.bin
+}
+
+func TestBucket_Sizes(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucketFull([]int{100, 10, 1000})
+	pooler := pool.Pooler(bytepool.BucketPoolerOptions{})
+
+	diffFatal(t, bytepool.SizeSet{10, 100, 1000}, pool.Sizes())
+	diffFatal(t, bytepool.SizeSet{10, 100, 1000}, pooler.Sizes())
+	diffFatal(t, 0, len(pool.Stats().Buckets)) // no traffic.
+
+	sizes := pool.Sizes()
+	sizes[0] = 5
+	diffFatal(t, bytepool.SizeSet{10, 100, 1000}, pool.Sizes())
+}
+
+func TestBucketPooler_chooser(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucket(8, 64)
+	diffFatal(t, bytepool.SizeSet{8, 16, 32, 64}, pool.Sizes())
+
+	chooser := &fixedChooser{bin: 2}
+	pooler := pool.Pooler(bytepool.BucketPoolerOptions{Chooser: chooser})
+
+	for _, l := range []int{1, 9, 64, 65} {
+		b := pooler.Get()
+		b.B = append(b.B, make([]byte, l)...)
+		b.Release()
+	}
+	diffFatal(t, []int{0, 1, 3}, chooser.observed) // over not observed
+	diffFatal(t, 32, pooler.Stats().DefaultSize)
+
+	chooser.bin = 100 // clamped
+	pooler.Get().Release()
+	diffFatal(t, 64, pooler.Stats().DefaultSize)
+}
+
+func TestBucketPooler_SetDefaultSize(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucket(8, 64)
+	pooler := pool.Pooler(bytepool.BucketPoolerOptions{ChooseInc: 10})
+
+	release := func(l, n int) {
+		for range n {
+			b := pooler.Get()
+			b.B = append(b.B, make([]byte, l)...)
+			b.Release()
+		}
+	}
+
+	pooler.SetDefaultSize(20)
+	diffFatal(t, 32, pooler.Stats().DefaultSize)
+	release(8, 100)
+	diffFatal(t, 32, pooler.Stats().DefaultSize)
+
+	pooler.SetDefaultSize(1000)
+	diffFatal(t, 64, pooler.Stats().DefaultSize)
+
+	pooler.UnpinDefaultSize()
+	release(8, 10)
+	diffFatal(t, 8, pooler.Stats().DefaultSize)
+}
+
+func TestBucketPooler_freeze(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucket(8, 64)
+	pooler := pool.Pooler(bytepool.BucketPoolerOptions{ChooseInc: 10})
+	var _ bytepool.Calibrator = pooler
+
+	release := func(l, n int) {
+		for range n {
+			b := pooler.Get()
+			b.B = append(b.B, make([]byte, l)...)
+			b.Release()
+		}
+	}
+	release(64, 100)
+	diffFatal(t, 64, pooler.Stats().DefaultSize)
+
+	pooler.Freeze()
+	release(8, 1000)
+	diffFatal(t, 64, pooler.Stats().DefaultSize)
+	for _, bin := range pooler.Stats().Bins {
+		if bin.Size == 8 && bin.Puts > 0 {
+			t.Fatal("recorded", bin)
+		}
+	}
+
+	pooler.Unfreeze()
+	release(8, 1000)
+	diffFatal(t, 8, pooler.Stats().DefaultSize)
+}
+
+func TestBucketPooler_calibration(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucket(8, 64)
+	pooler := pool.Pooler(bytepool.BucketPoolerOptions{ChooseInc: 10})
+
+	diffFatal(t, bytepool.CalibrationStats{}, pooler.Stats().Calibration)
+
+	release := func(l, n int) {
+		for range n {
+			b := pooler.Get()
+			b.B = append(b.B, make([]byte, l)...)
+			b.Release()
+		}
+	}
+
+	before := time.Now()
+	release(8, 100)
+	c := pooler.Stats().Calibration
+	if c.Elections == 0 || c.LastElection.Before(before) {
+		t.Fatal("no election", c)
+	}
+	if !c.LastChange.IsZero() || c.PreviousDefaultSize != 0 {
+		t.Fatal("changed", c)
+	}
+
+	release(64, 1000)
+	c2 := pooler.Stats().Calibration
+	diffFatal(t, 64, pooler.Stats().DefaultSize)
+	diffFatal(t, 8, c2.PreviousDefaultSize)
+	if c2.Elections <= c.Elections || c2.LastChange.Before(c.LastElection) || c2.LastElection.Before(c2.LastChange) {
+		t.Fatal("elections", c, c2)
+	}
+
+	pooler.Freeze()
+	pooler.SetDefaultSize(16)
+	c3 := pooler.Stats().Calibration
+	if !c3.Pinned || !c3.Frozen {
+		t.Fatal("not pinned and frozen", c3)
+	}
+	release(8, 100)
+	diffFatal(t, c2.Elections, pooler.Stats().Calibration.Elections)
+}
+
+func TestBucketPooler_onDefaultSizeChange(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	var changes []bytepool.DefaultSizeChange
+	pool := bytepool.NewBucket(8, 64)
+	pooler := pool.Pooler(bytepool.BucketPoolerOptions{
+		ChooseInc:           10,
+		OnDefaultSizeChange: func(c bytepool.DefaultSizeChange) { changes = append(changes, c) },
+	})
+
+	for range 1000 {
+		b := pooler.Get()
+		b.B = append(b.B, make([]byte, 64)...)
+		b.Release()
+	}
+	diffFatal(t, 1, len(changes))
+	diffFatal(t, [2]int{8, 64}, [2]int{changes[0].Old, changes[0].New})
+	diffFatal(t, 64, changes[0].Stats.DefaultSize)
+	diffFatal(t, 8, changes[0].Stats.Calibration.PreviousDefaultSize)
+
+	pooler.SetDefaultSize(16)
+	pooler.SetDefaultSize(16) // unchanged.
+	diffFatal(t, 2, len(changes))
+	diffFatal(t, [2]int{64, 16}, [2]int{changes[1].Old, changes[1].New})
+	diffFatal(t, true, changes[1].Stats.Calibration.Pinned)
+}
+
+func TestBucket_bypassAbove(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8, 16, 64}, bytepool.BucketPoolOptions{
+		MaxRetained: 4,
+		BypassAbove: 20,
+	})
+
+	b := pool.GetGrown(30) // exact, not the 64 bucket.
+	diffFatal(t, 30, cap(b.B))
+	b.Release()
+
+	b = pool.GetGrown(10)
+	diffFatal(t, 16, cap(b.B))
+	b.Release()
+
+	b = pool.GetGrown(100) // still an over.
+	b.Release()
+
+	s := pool.Stats()
+	diffFatal(t, [4]uint64{2, 2, 3, 3}, [4]uint64{s.Bypassed, s.Overs, s.Puts, s.Gets})
+	diffFatal(t, 1, s.Pooled)
+	diffFatal(t, []bytepool.BucketContents{
+		{Size: 8, Exact: true},
+		{Size: 16, Pooled: 1, Exact: true},
+		{Size: 64, Exact: true},
+	}, pool.Inspect())
+}
+
+func TestBucket_bypassBelow(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8, 64}, bytepool.BucketPoolOptions{
+		MaxRetained: 4,
+		BypassBelow: 32,
+	})
+
+	b := pool.GetGrown(16) // exact, not the 64 bucket.
+	diffFatal(t, 16, cap(b.B))
+	b.Release()
+
+	b = pool.GetGrown(40)
+	diffFatal(t, 64, cap(b.B))
+	b.B = b.B[:0:8] // shrunk under the floor, discarded.
+	b.Release()
+
+	s := pool.Stats()
+	diffFatal(t, [4]uint64{3, 0, 2, 2}, [4]uint64{s.Bypassed, s.Overs, s.Puts, s.Gets})
+	diffFatal(t, 0, s.Pooled)
+}
+
+func TestBucket_probeLarger(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8, 16, 32, 64}, bytepool.BucketPoolOptions{
+		MaxRetained: 4,
+		ProbeLarger: 2,
+		BypassAbove: 32,
+	})
+
+	pool.GetGrown(40).Release() // bypassed, never probed.
+	pool.GetGrown(20).Release()
+
+	b := pool.GetFilled(5)
+	diffFatal(t, 5, len(b.B))
+	diffFatal(t, 32, cap(b.B)) // retained larger, over allocating an 8.
+	b.Release()
+
+	b1 := pool.GetGrown(5)
+	b2 := pool.GetGrown(5) // nothing retained in range.
+	diffFatal(t, []int{32, 8}, []int{cap(b1.B), cap(b2.B)})
+	b1.Release()
+	b2.Release()
+
+	b = pool.GetGrown(1) // first fit retained.
+	diffFatal(t, 8, cap(b.B))
+	diffFatal(t, 1, int(pool.Stats().Buckets[0].Misses))
+}
+
+func TestBucket_probeLargerRecentHits(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8, 16, 32, 64}, bytepool.BucketPoolOptions{
+		MaxRetained: 4,
+		ProbeLarger: 3,
+	})
+
+	pool.GetGrown(16).Release() // a miss, then retained.
+	for range 3 {
+		pool.GetGrown(64).Release() // hits after the first.
+	}
+
+	b := pool.GetGrown(1)
+	diffFatal(t, 64, cap(b.B)) // most recent hits, over the nearer 16.
+	b.Release()
+
+	b = pool.GetGrownAtMost(1, 16)
+	diffFatal(t, 16, cap(b.B)) // 64 over maxCap.
+}
+
+func TestBucket_fillStats(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{100}, bytepool.BucketPoolOptions{FillStats: true})
+	for _, l := range []int{0, 10, 25, 26, 50, 75, 76, 100, 100} {
+		b := pool.GetFilled(l)
+		b.Release()
+	}
+	diffFatal(t, [4]uint64{3, 2, 1, 3}, pool.Stats().Buckets[0].Fill)
+}
@@ -0,0 +1,59 @@
+package bytepool
+
+import (
+	"unsafe"
+)
+
+// Builds a string in pooled Bytes, growing through the pool's sizes rather than append doubling.
+// Call Release or Detach when done.
+type Builder struct {
+	a Appender
+}
+
+func NewBuilder(p SizedPooler) *Builder {
+	return &Builder{a: Appender{p: p}}
+}
+
+// Ensures room for n more bytes.
+func (s *Builder) Grow(n int) {
+	s.a.Grow(n)
+}
+
+func (s *Builder) Write(p []byte) (int, error) {
+	s.a.Append(p...)
+	return len(p), nil
+}
+
+func (s *Builder) WriteString(str string) (int, error) {
+	s.a.AppendString(str)
+	return len(str), nil
+}
+
+func (s *Builder) WriteByte(c byte) error {
+	s.a.Append(c)
+	return nil
+}
+
+func (s *Builder) Len() int {
+	return s.a.Len()
+}
+
+// Copy of the built string, the Builder remains usable.
+func (s *Builder) String() string {
+	return string(s.a.Bytes())
+}
+
+// Built string without copying. The buffer leaves the pool and the Builder is reset.
+// Not for pools using an Allocator, whose memory is freed once unreachable.
+func (s *Builder) Detach() string {
+	b := s.a.Take()
+	if b == nil {
+		return ""
+	}
+	return unsafe.String(unsafe.SliceData(b.B), len(b.B))
+}
+
+// Returns the buffer to the pool, the Builder is reset.
+func (s *Builder) Release() {
+	s.a.Release()
+}
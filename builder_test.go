@@ -0,0 +1,50 @@
+package bytepool_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(4, 128)
+	s := bytepool.NewBuilder(pool)
+
+	diffFatal(t, "", s.String())
+	diffFatal(t, 0, s.Len())
+
+	var want strings.Builder
+	for i := range 40 {
+		b := byte('a' + i%26)
+		if err := s.WriteByte(b); err != nil {
+			t.Fatal(err)
+		}
+		want.WriteByte(b)
+		if i%3 == 0 {
+			if _, err := s.WriteString("xy"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.Write([]byte("z")); err != nil {
+				t.Fatal(err)
+			}
+			want.WriteString("xyz")
+		}
+	}
+	diffFatal(t, want.String(), s.String())
+	diffFatal(t, want.Len(), s.Len())
+
+	got := s.Detach()
+	diffFatal(t, want.String(), got)
+	diffFatal(t, "", s.String())
+
+	s.WriteString("abc")
+	diffFatal(t, "abc", s.String())
+	s.Release()
+	diffFatal(t, "", s.String())
+
+	stats := pool.Stats()
+	diffFatal(t, uint64(0), stats.Overs)
+}
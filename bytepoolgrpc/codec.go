@@ -0,0 +1,97 @@
+// Package bytepoolgrpc is a gRPC proto codec marshaling into pooled Bytes, returned to the
+// pool once the transport frees them.
+//
+// A separate module so bytepool does not depend on gRPC.
+package bytepoolgrpc
+
+import (
+	"fmt"
+
+	"github.com/graxinc/bytepool"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/mem"
+	"google.golang.org/protobuf/proto"
+)
+
+// Name of the codec, replacing gRPC's default proto codec when registered.
+const Name = "proto"
+
+// Registers a codec over p, replacing gRPC's default proto codec. Call from init, as
+// encoding.RegisterCodecV2 is not safe for concurrent use.
+func Register(p bytepool.SizedPooler) {
+	encoding.RegisterCodecV2(NewCodec(p))
+}
+
+// Codec marshaling proto messages into Bytes from p and unmarshaling from buffers
+// materialized into Bytes from p.
+func NewCodec(p bytepool.SizedPooler) encoding.CodecV2 {
+	return codec{pool: BufferPool(p)}
+}
+
+type codec struct {
+	pool mem.BufferPool
+}
+
+func (c codec) Marshal(v any) (mem.BufferSlice, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("bytepoolgrpc: marshal: %T is not a proto.Message", v)
+	}
+	size := proto.Size(m)
+	if mem.IsBelowBufferPoolingThreshold(size) { // mem.NewBuffer would not return it to the pool.
+		b, err := proto.Marshal(m)
+		if err != nil {
+			return nil, fmt.Errorf("bytepoolgrpc: marshal: %w", err)
+		}
+		return mem.BufferSlice{mem.SliceBuffer(b)}, nil
+	}
+	buf := c.pool.Get(size)
+	b, err := proto.MarshalOptions{}.MarshalAppend((*buf)[:0], m)
+	if err != nil {
+		c.pool.Put(buf)
+		return nil, fmt.Errorf("bytepoolgrpc: marshal: %w", err)
+	}
+	*buf = b // same backing array, as sized by proto.Size.
+	return mem.BufferSlice{mem.NewBuffer(buf, c.pool)}, nil
+}
+
+func (c codec) Unmarshal(data mem.BufferSlice, v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("bytepoolgrpc: unmarshal: %T is not a proto.Message", v)
+	}
+	var b []byte
+	if len(data) > 1 && mem.IsBelowBufferPoolingThreshold(data.Len()) {
+		b = data.Materialize() // as Marshal.
+	} else {
+		buf := data.MaterializeToBuffer(c.pool)
+		defer buf.Free()
+		b = buf.ReadOnlyData()
+	}
+	if err := proto.Unmarshal(b, m); err != nil {
+		return fmt.Errorf("bytepoolgrpc: unmarshal: %w", err)
+	}
+	return nil
+}
+
+func (codec) Name() string {
+	return Name
+}
+
+// A mem.BufferPool over p, also usable with grpc.WithRecvBufferPool.
+// gRPC does not return buffers under mem's pooling threshold.
+func BufferPool(p bytepool.SizedPooler) mem.BufferPool {
+	return bufferPool{p}
+}
+
+type bufferPool struct {
+	pool bytepool.SizedPooler
+}
+
+func (p bufferPool) Get(length int) *[]byte {
+	return &p.pool.GetFilled(length).B
+}
+
+func (p bufferPool) Put(buf *[]byte) {
+	bytepool.BytesOf(buf).Release()
+}
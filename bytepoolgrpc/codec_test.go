@@ -0,0 +1,57 @@
+package bytepoolgrpc_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+	"github.com/graxinc/bytepool/bytepoolgrpc"
+	"google.golang.org/grpc/mem"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestCodec(t *testing.T) {
+	pool := bytepool.NewBucketOptions([]int{64, 1024, 8192}, bytepool.BucketPoolOptions{MaxRetained: 10})
+	codec := bytepoolgrpc.NewCodec(pool)
+	if codec.Name() != "proto" {
+		t.Fatal(codec.Name())
+	}
+
+	for _, l := range []int{10, 5000} {
+		in := wrapperspb.Bytes(make([]byte, l))
+		data, err := codec.Marshal(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data.Len() != proto.Size(in) {
+			t.Fatal(data.Len())
+		}
+
+		var out wrapperspb.BytesValue
+		if err := codec.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(in, &out) {
+			t.Fatal(l)
+		}
+		data.Free()
+	}
+
+	s := pool.Stats()
+	if s.Outstanding != 0 || s.Gets != 1 || s.Pooled != 1 { // only the large, over mem's threshold.
+		t.Fatal(s)
+	}
+
+	split := mem.BufferSlice{mem.SliceBuffer{0x0a, 1}, mem.SliceBuffer{'a'}} // field 1, length 1.
+	var out wrapperspb.BytesValue
+	if err := codec.Unmarshal(split, &out); err != nil {
+		t.Fatal(err)
+	}
+	if string(out.Value) != "a" {
+		t.Fatal(&out)
+	}
+
+	if _, err := codec.Marshal("not proto"); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,13 @@
+module github.com/graxinc/bytepool/bytepoolgrpc
+
+go 1.23
+
+require (
+	github.com/graxinc/bytepool v0.0.0
+	google.golang.org/grpc v1.66.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require golang.org/x/sys v0.21.0 // indirect
+
+replace github.com/graxinc/bytepool => ../
@@ -0,0 +1,41 @@
+package bytepoolhttp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/graxinc/bytepool"
+)
+
+// Sets srv.ConnContext and srv.ConnState, keeping any already set, so each connection gets a
+// bytepool.ConnPooler over pool, closed with the connection. Handlers retrieve it with
+// bytepool.PoolerFromContext(r.Context()).
+//
+// Call before serving.
+func ConnPoolers(srv *http.Server, pool *bytepool.BucketPool, o bytepool.ConnPoolerOptions) {
+	var conns sync.Map // net.Conn to *bytepool.ConnPooler
+
+	connContext := srv.ConnContext
+	srv.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+		if connContext != nil {
+			ctx = connContext(ctx, c)
+		}
+		p := pool.ConnPooler(o)
+		conns.Store(c, p)
+		return bytepool.WithPooler(ctx, p)
+	}
+
+	connState := srv.ConnState
+	srv.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateClosed || state == http.StateHijacked {
+			if p, ok := conns.LoadAndDelete(c); ok {
+				p.(*bytepool.ConnPooler).Close()
+			}
+		}
+		if connState != nil {
+			connState(c, state)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package bytepoolhttp_test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+	"github.com/graxinc/bytepool/bytepoolhttp"
+)
+
+func TestConnPoolers(t *testing.T) {
+	requireStats(t)
+	pool := bytepool.NewBucketOptions([]int{8, 64}, bytepool.BucketPoolOptions{MaxRetained: 100})
+
+	var (
+		mu      sync.Mutex
+		poolers = map[*bytepool.ConnPooler]bool{}
+		closed  = make(chan struct{}, 10)
+	)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := bytepool.PoolerFromContext(r.Context()).(*bytepool.ConnPooler)
+		mu.Lock()
+		poolers[p] = true
+		mu.Unlock()
+
+		b := p.GetGrown(50)
+		b.B = append(b.B, "hello"...)
+		w.Write(b.B)
+		b.Release()
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) { // kept
+		if state == http.StateClosed {
+			closed <- struct{}{}
+		}
+	}
+	bytepoolhttp.ConnPoolers(srv.Config, pool, bytepool.ConnPoolerOptions{})
+	srv.Start()
+
+	get := func(c *http.Client) {
+		resp, err := c.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != "hello" {
+			t.Fatal(string(body))
+		}
+	}
+	c1, c2 := srv.Client(), &http.Client{Transport: &http.Transport{}}
+	get(c1)
+	get(c1) // same connection
+	get(c2)
+
+	mu.Lock()
+	if len(poolers) != 2 {
+		t.Fatal(len(poolers))
+	}
+	mu.Unlock()
+
+	srv.CloseClientConnections()
+	srv.Close()
+	<-closed
+	<-closed
+
+	for p := range poolers {
+		if s := p.Stats(); s.Local.Retained != 0 {
+			t.Fatal("not closed", s)
+		}
+	}
+	if s := pool.Stats(); s.Pooled != 2 {
+		t.Fatal(s.Pooled)
+	}
+}
@@ -0,0 +1,168 @@
+// Package bytepoolhttp serves registered pools for debugging, similar to net/http/pprof.
+package bytepoolhttp
+
+import (
+	"encoding/json"
+	"html/template"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/graxinc/bytepool"
+)
+
+// Renders metrics of every pool from bytepool.Register, as HTML or as JSON with ?format=json
+// or an Accept of application/json.
+//
+// POST with form values action (reset or drain) and pool (a registered name) resets stats or
+// drains retained Bytes when the pool supports it. Cross-origin POSTs from browsers are
+// rejected by their Sec-Fetch-Site or Origin headers.
+//
+//	mux.Handle("/debug/bytepool", bytepoolhttp.Handler())
+func Handler() http.Handler {
+	return http.HandlerFunc(serve)
+}
+
+type pool struct {
+	Name    string
+	Metrics []bytepool.Metric
+	Actions []string
+}
+
+func serve(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPost:
+		act(w, r)
+		return
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	regs := bytepool.Registered()
+	var pools []pool
+	for name, mr := range regs {
+		p := pool{Name: name, Metrics: mr.AppendMetrics(nil)}
+		if _, ok := mr.(resetter); ok {
+			p.Actions = append(p.Actions, "reset")
+		}
+		if _, ok := mr.(drainer); ok {
+			p.Actions = append(p.Actions, "drain")
+		}
+		pools = append(pools, p)
+	}
+	slices.SortFunc(pools, func(a, b pool) int {
+		if a.Name < b.Name {
+			return -1
+		}
+		if a.Name > b.Name {
+			return 1
+		}
+		return 0
+	})
+
+	if r.FormValue("format") == "json" || acceptsJSON(r.Header.Get("Accept")) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(pools)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = page.Execute(w, pools)
+}
+
+// Whether accept lists application/json, ignoring parameters other than a zero q.
+func acceptsJSON(accept string) bool {
+	for _, a := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(a)
+		if err != nil || mt != "application/json" {
+			continue
+		}
+		if q, ok := params["q"]; !ok || strings.TrimRight(q, "0.") != "" {
+			return true
+		}
+	}
+	return false
+}
+
+type resetter interface {
+	ResetStats()
+}
+
+type drainer interface {
+	Drain() int
+}
+
+// Whether a browser sent r from another origin. Requests without either header are from
+// non-browser clients or old browsers.
+func crossOrigin(r *http.Request) bool {
+	switch r.Header.Get("Sec-Fetch-Site") {
+	case "":
+	case "same-origin", "none":
+		return false
+	default:
+		return true
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+	u, err := url.Parse(origin)
+	return err != nil || u.Host != r.Host
+}
+
+func act(w http.ResponseWriter, r *http.Request) {
+	if crossOrigin(r) {
+		http.Error(w, "cross-origin request", http.StatusForbidden)
+		return
+	}
+	mr, ok := bytepool.Registered()[r.FormValue("pool")]
+	if !ok {
+		http.Error(w, "unknown pool", http.StatusNotFound)
+		return
+	}
+
+	switch action := r.FormValue("action"); action {
+	case "reset":
+		rs, ok := mr.(resetter)
+		if !ok {
+			http.Error(w, "reset not supported", http.StatusBadRequest)
+			return
+		}
+		rs.ResetStats()
+	case "drain":
+		d, ok := mr.(drainer)
+		if !ok {
+			http.Error(w, "drain not supported", http.StatusBadRequest)
+			return
+		}
+		d.Drain()
+	default:
+		http.Error(w, "unknown action", http.StatusBadRequest)
+		return
+	}
+	http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+}
+
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head><title>bytepool</title></head>
+<body>
+{{range .}}
+<h2>{{.Name}}</h2>
+<table>
+{{range .Metrics}}<tr><td>{{.Name}}</td><td>{{.Value}}</td></tr>
+{{end}}
+</table>
+{{$name := .Name}}{{range .Actions}}
+<form method="post"><input type="hidden" name="pool" value="{{$name}}"><button name="action" value="{{.}}">{{.}}</button></form>
+{{end}}
+{{else}}
+<p>No registered pools.</p>
+{{end}}
+</body>
+</html>
+`))
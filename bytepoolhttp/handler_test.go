@@ -0,0 +1,115 @@
+package bytepoolhttp_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/graxinc/bytepool"
+	"github.com/graxinc/bytepool/bytepoolhttp"
+)
+
+func TestHandler(t *testing.T) {
+	requireStats(t)
+	pool := bytepool.NewBucket(2, 8)
+	bytepool.Register("test", pool)
+	defer bytepool.Unregister("test")
+
+	pool.GetGrown(3).Release()
+
+	srv := httptest.NewServer(bytepoolhttp.Handler())
+	defer srv.Close()
+
+	getJSON := func() map[string]uint64 {
+		resp, err := http.Get(srv.URL + "?format=json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		var pools []struct {
+			Name    string
+			Metrics []bytepool.Metric
+			Actions []string
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&pools); err != nil {
+			t.Fatal(err)
+		}
+		if len(pools) != 1 || pools[0].Name != "test" {
+			t.Fatal(pools)
+		}
+		diffFatal(t, []string{"reset", "drain"}, pools[0].Actions)
+
+		m := make(map[string]uint64)
+		for _, metric := range pools[0].Metrics {
+			m[metric.Name] = metric.Value
+		}
+		return m
+	}
+	diffFatal(t, uint64(1), getJSON()["/bytepool/bucket/misses:gets"])
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	diffFatal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	for accept, want := range map[string]string{
+		"text/html, application/json;q=0.9": "application/json",
+		"Application/JSON; charset=utf-8":   "application/json",
+		"application/json;q=0, text/html":   "text/html; charset=utf-8",
+	} {
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Accept", accept)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		diffFatal(t, want, resp.Header.Get("Content-Type"))
+	}
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("pool=test&action=reset"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", "http://example.com")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	diffFatal(t, http.StatusForbidden, resp.StatusCode)
+	diffFatal(t, uint64(1), getJSON()["/bytepool/bucket/misses:gets"]) // not reset.
+
+	resp, err = http.PostForm(srv.URL, url.Values{"pool": {"test"}, "action": {"reset"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	diffFatal(t, http.StatusOK, resp.StatusCode) // after redirect
+	diffFatal(t, uint64(0), getJSON()["/bytepool/bucket/misses:gets"])
+
+	resp, err = http.Post(srv.URL, "application/x-www-form-urlencoded", strings.NewReader("pool=other&action=drain"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	diffFatal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func diffFatal(t testing.TB, want, got any) {
+	t.Helper()
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("(-want +got):\n%v", d)
+	}
+}
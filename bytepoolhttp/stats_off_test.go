@@ -0,0 +1,11 @@
+//go:build bytepool_nostats
+
+package bytepoolhttp_test
+
+import "testing"
+
+// Skips tests asserting Stats values, which are zero without stats.
+func requireStats(t *testing.T) {
+	t.Helper()
+	t.Skip("asserts Stats, off with bytepool_nostats")
+}
@@ -0,0 +1,252 @@
+// Package bytepooltest checks the bytepool contracts against any pool implementation,
+// including third party ones built on the bytepool interfaces.
+//
+//	func TestMyPool(t *testing.T) {
+//		bytepooltest.TestPooler(t, func() bytepool.Pooler { return NewMyPool() })
+//	}
+package bytepooltest
+
+import (
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+// Runs the SizedPooler checks as subtests, each against a new pool.
+func TestSizedPooler(t *testing.T, newPool func() bytepool.SizedPooler) {
+	t.Run("grown", func(t *testing.T) {
+		CheckGrown(t, newPool())
+	})
+	t.Run("filled", func(t *testing.T) {
+		CheckFilled(t, newPool())
+	})
+	t.Run("no_aliasing", func(t *testing.T) {
+		CheckNoAliasing(t, newPool())
+	})
+	t.Run("concurrent", func(t *testing.T) {
+		CheckConcurrent(t, newPool())
+	})
+}
+
+// Runs TestSizedPooler and the Pooler checks as subtests, each against a new pool.
+func TestPooler(t *testing.T, newPool func() bytepool.Pooler) {
+	TestSizedPooler(t, func() bytepool.SizedPooler { return newPool() })
+	t.Run("get", func(t *testing.T) {
+		CheckGet(t, newPool())
+	})
+}
+
+// Runs TestSizedPooler and the SizedPool checks as subtests, each against a new pool.
+func TestSizedPool(t *testing.T, newPool func() bytepool.SizedPool) {
+	TestSizedPooler(t, func() bytepool.SizedPooler { return newPool() })
+	t.Run("reuses", func(t *testing.T) {
+		CheckReuses(t, newPool())
+	})
+	t.Run("put", func(t *testing.T) {
+		CheckPut(t, newPool())
+	})
+	t.Run("adopt", func(t *testing.T) {
+		CheckAdopt(t, newPool())
+	})
+}
+
+// Runs TestPooler and TestSizedPool as subtests, each against a new pool.
+func TestPool(t *testing.T, newPool func() bytepool.Pool) {
+	TestSizedPool(t, func() bytepool.SizedPool { return newPool() })
+	t.Run("get", func(t *testing.T) {
+		CheckGet(t, newPool())
+	})
+}
+
+// A released Bytes is handed out again. Retries as pools can drop, such as sync.Pool.
+func CheckReuses(t testing.TB, pool bytepool.SizedPooler) {
+	t.Helper()
+
+	for range 1000 {
+		b1 := pool.GetGrown(8)
+		b1.Release()
+		b2 := pool.GetGrown(8)
+		b2.Release()
+		if b1 == b2 {
+			return
+		}
+	}
+	t.Fatalf("released Bytes not reused")
+}
+
+// Put takes Bytes from another pool, whose later Releases return to this pool. Put of nil is ignored.
+func CheckPut(t testing.TB, pool bytepool.SizedPool) {
+	t.Helper()
+
+	other := bytepool.NewSync()
+	pool.Put(nil)
+	for range 1000 {
+		b1 := other.GetGrown(8)
+		pool.Put(b1)
+		b2 := pool.GetGrown(8)
+		b2.Release()
+		if b1 != b2 {
+			continue
+		}
+		b3 := pool.GetGrown(8) // b2 returned here, not to other.
+		b3.Release()
+		if b3 == b2 {
+			return
+		}
+	}
+	t.Fatalf("put Bytes not reused")
+}
+
+// Adopt keeps B and makes Releases return to this pool. Adopt of nil is ignored.
+func CheckAdopt(t testing.TB, pool bytepool.SizedPool) {
+	t.Helper()
+
+	other := bytepool.NewSync()
+	pool.Adopt(nil)
+	for range 1000 {
+		b1 := other.GetGrown(8)
+		b1.B = append(b1.B, 1, 2, 3)
+		pool.Adopt(b1)
+		if string(b1.B) != "\x01\x02\x03" {
+			t.Fatalf("Adopt changed B to %v", b1.B)
+		}
+		b1.Release()
+		b2 := pool.GetGrown(8)
+		b2.Release()
+		if b1 == b2 {
+			return
+		}
+	}
+	t.Fatalf("adopted Bytes not reused")
+}
+
+// GetGrown returns zero length and at least the requested capacity, including after
+// Releases of modified Bytes.
+func CheckGrown(t testing.TB, pool bytepool.SizedPooler) {
+	t.Helper()
+
+	rando := rand.New(rand.NewPCG(0, 0))
+	for range 1000 {
+		c := rando.IntN(1000)
+		b := pool.GetGrown(c)
+		if len(b.B) != 0 {
+			t.Fatalf("GetGrown(%v) len %v, want 0", c, len(b.B))
+		}
+		if cap(b.B) < c {
+			t.Fatalf("GetGrown(%v) cap %v, want >= %v", c, cap(b.B), c)
+		}
+		mutate(rando, b)
+		b.Release()
+	}
+}
+
+// GetFilled returns exactly the requested length, including after Releases of modified Bytes.
+func CheckFilled(t testing.TB, pool bytepool.SizedPooler) {
+	t.Helper()
+
+	rando := rand.New(rand.NewPCG(0, 0))
+	for range 1000 {
+		l := rando.IntN(1000)
+		b := pool.GetFilled(l)
+		if len(b.B) != l {
+			t.Fatalf("GetFilled(%v) len %v", l, len(b.B))
+		}
+		mutate(rando, b)
+		b.Release()
+	}
+}
+
+// Get returns zero length, including after Releases of modified Bytes.
+func CheckGet(t testing.TB, pool bytepool.Pooler) {
+	t.Helper()
+
+	rando := rand.New(rand.NewPCG(0, 0))
+	for range 1000 {
+		b := pool.Get()
+		if len(b.B) != 0 {
+			t.Fatalf("Get len %v, want 0", len(b.B))
+		}
+		mutate(rando, b)
+		b.Release()
+	}
+}
+
+// Bytes held at the same time never share memory.
+func CheckNoAliasing(t testing.TB, pool bytepool.SizedPooler) {
+	t.Helper()
+
+	rando := rand.New(rand.NewPCG(0, 0))
+	for range 100 {
+		held := make([]*bytepool.Bytes, 1+rando.IntN(20))
+		for i := range held {
+			held[i] = pool.GetFilled(1 + rando.IntN(100))
+			fill(held[i], byte(i))
+		}
+		for i, b := range held {
+			if !filled(b, byte(i)) {
+				t.Fatalf("held Bytes %v modified by another", i)
+			}
+		}
+		for _, b := range held {
+			b.Release()
+		}
+	}
+}
+
+// Concurrent Gets and Releases never hand out Bytes still held elsewhere.
+func CheckConcurrent(t testing.TB, pool bytepool.SizedPooler) {
+	t.Helper()
+
+	var wait sync.WaitGroup
+	errs := make(chan string, 10)
+	for g := range 10 {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			rando := rand.New(rand.NewPCG(uint64(g), 0))
+			for range 1000 {
+				b := pool.GetFilled(1 + rando.IntN(100))
+				fill(b, byte(g))
+				for range 10 { // some time for concurrent mutation
+					if !filled(b, byte(g)) {
+						errs <- "concurrent modification"
+						return
+					}
+				}
+				b.Release()
+			}
+		}()
+	}
+	wait.Wait()
+	close(errs)
+	for e := range errs {
+		t.Fatal(e)
+	}
+}
+
+// Releases should tolerate any length and a replaced slice.
+func mutate(rando *rand.Rand, b *bytepool.Bytes) {
+	switch rando.IntN(3) {
+	case 0:
+		b.B = b.B[:cap(b.B)]
+	case 1:
+		b.B = make([]byte, rando.IntN(100))
+	}
+}
+
+func fill(b *bytepool.Bytes, v byte) {
+	for i := range b.B {
+		b.B[i] = v
+	}
+}
+
+func filled(b *bytepool.Bytes, v byte) bool {
+	for _, c := range b.B {
+		if c != v {
+			return false
+		}
+	}
+	return true
+}
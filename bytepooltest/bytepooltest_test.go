@@ -0,0 +1,78 @@
+package bytepooltest_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+	"github.com/graxinc/bytepool/bytepooltest"
+)
+
+func TestPools(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sync", func(t *testing.T) {
+		bytepooltest.TestPool(t, func() bytepool.Pool { return bytepool.NewSync() })
+	})
+	t.Run("dynamic", func(t *testing.T) {
+		bytepooltest.TestPool(t, func() bytepool.Pool { return bytepool.NewDynamic() })
+	})
+	t.Run("bucket", func(t *testing.T) {
+		bytepooltest.TestSizedPool(t, func() bytepool.SizedPool { return bytepool.NewBucket(1, 256) })
+	})
+	t.Run("bucket_pooler", func(t *testing.T) {
+		bytepooltest.TestPool(t, func() bytepool.Pool {
+			return bytepool.NewBucket(1, 256).Pooler(bytepool.BucketPoolerOptions{})
+		})
+	})
+	t.Run("bucket_retained", func(t *testing.T) {
+		bytepooltest.TestSizedPool(t, func() bytepool.SizedPool {
+			return bytepool.NewBucketOptions(bytepool.Pow2Sizes(1, 256), bytepool.BucketPoolOptions{MaxRetained: 4})
+		})
+	})
+	t.Run("secure", func(t *testing.T) {
+		bytepooltest.TestSizedPooler(t, func() bytepool.SizedPooler { return bytepool.NewSecure([]int{64, 256}, bytepool.SecureOptions{}) })
+	})
+	t.Run("auto", func(t *testing.T) {
+		bytepooltest.TestPool(t, func() bytepool.Pool { return bytepool.NewAuto(bytepool.AutoOptions{}) })
+	})
+}
+
+type aliasing struct {
+	b *bytepool.Bytes
+}
+
+func (a aliasing) GetGrown(c int) *bytepool.Bytes {
+	return &bytepool.Bytes{B: a.b.B[:0]}
+}
+
+func (a aliasing) GetFilled(length int) *bytepool.Bytes {
+	return &bytepool.Bytes{B: a.b.B[:length]}
+}
+
+func TestCheckNoAliasing_fails(t *testing.T) {
+	t.Parallel()
+
+	ft := &fatalTB{TB: t}
+	done := make(chan struct{})
+	go func() { // Fatal calls Goexit.
+		defer close(done)
+		bytepooltest.CheckNoAliasing(ft, aliasing{&bytepool.Bytes{B: make([]byte, 100)}})
+	}()
+	<-done
+	if !ft.failed {
+		t.Fatal("expected failure")
+	}
+}
+
+type fatalTB struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fatalTB) Helper() {}
+
+func (f *fatalTB) Fatalf(string, ...any) {
+	f.failed = true
+	runtime.Goexit()
+}
@@ -0,0 +1,225 @@
+package bytepooltest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+type HarnessOptions struct {
+	Goroutines int    // defaults to 8.
+	Ops        int    // per goroutine, defaults to 2000.
+	MaxSize    int    // of requests, defaults to 1024.
+	Seed       uint64 // of the op sequences.
+
+	// Snapshot of counters that must never decrease, such as Gets and Puts from Stats.
+	// Sampled concurrently with the ops. Optional.
+	Counters func() []uint64
+}
+
+// Drives random interleavings of Get, GetGrown, GetFilled, Put, Adopt and Release across
+// goroutines, checking len and cap contracts, that held Bytes are never modified by others,
+// and that Counters never decrease. Get, Put and Adopt are used when pool implements them.
+func CheckHarness(t testing.TB, pool bytepool.SizedPooler, o HarnessOptions) {
+	t.Helper()
+
+	if o.Goroutines <= 0 {
+		o.Goroutines = 8
+	}
+	if o.Ops <= 0 {
+		o.Ops = 2000
+	}
+	if o.MaxSize <= 0 {
+		o.MaxSize = 1024
+	}
+
+	var wait sync.WaitGroup
+	errs := make(chan string, o.Goroutines+1)
+	stop := make(chan struct{})
+
+	var counters sync.WaitGroup
+	if o.Counters != nil {
+		counters.Add(1)
+		go func() {
+			defer counters.Done()
+			var prev []uint64
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				cur := o.Counters()
+				if err := checkMonotonic(prev, cur); err != "" {
+					errs <- err
+					return
+				}
+				prev = cur
+			}
+		}()
+	}
+
+	for g := range o.Goroutines {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			rando := rand.New(rand.NewPCG(o.Seed, uint64(g)))
+			d := newDriver(pool, o.MaxSize, byte(g))
+			defer d.releaseAll()
+			for range o.Ops {
+				if err := d.step(byte(rando.Uint32()), rando.IntN(o.MaxSize+1)); err != "" {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wait.Wait()
+	close(stop)
+	counters.Wait()
+	close(errs)
+	for e := range errs {
+		t.Fatalf("%v", e)
+	}
+}
+
+// Interprets ops as a sequence of Get, GetGrown, GetFilled, Put, Adopt and Release, checking
+// the same contracts as CheckHarness on one goroutine. Suits fuzz targets:
+//
+//	f.Fuzz(func(t *testing.T, ops []byte) {
+//		bytepooltest.CheckOps(t, NewMyPool(), ops)
+//	})
+func CheckOps(t testing.TB, pool bytepool.SizedPooler, ops []byte) {
+	t.Helper()
+
+	d := newDriver(pool, 1<<16, 0)
+	defer d.releaseAll()
+	for len(ops) >= 3 {
+		size := int(ops[1])<<8 | int(ops[2])
+		if err := d.step(ops[0], size); err != "" {
+			t.Fatalf("%v", err)
+		}
+		ops = ops[3:]
+	}
+}
+
+func checkMonotonic(prev, cur []uint64) string {
+	if prev == nil {
+		return ""
+	}
+	if len(prev) != len(cur) {
+		return fmt.Sprintf("Counters changed length from %v to %v", len(prev), len(cur))
+	}
+	for i := range cur {
+		if cur[i] < prev[i] {
+			return fmt.Sprintf("Counters[%v] decreased from %v to %v", i, prev[i], cur[i])
+		}
+	}
+	return ""
+}
+
+const maxHeld = 16
+
+type held struct {
+	b *bytepool.Bytes
+	v byte
+}
+
+// Holds Bytes filled with values mostly unique among drivers and their holdings, so overlaps show.
+type driver struct {
+	pool    bytepool.SizedPooler
+	pooler  bytepool.Pooler    // nil when pool is not one.
+	sized   bytepool.SizedPool // nil when pool is not one.
+	other   bytepool.Pool      // origin of Bytes given to Put and Adopt.
+	maxSize int
+	tag     byte // distinguishes drivers sharing a pool.
+	held    []held
+	next    byte
+}
+
+func newDriver(pool bytepool.SizedPooler, maxSize int, tag byte) *driver {
+	d := &driver{pool: pool, other: bytepool.NewSync(), maxSize: maxSize, tag: tag}
+	d.pooler, _ = pool.(bytepool.Pooler)
+	d.sized, _ = pool.(bytepool.SizedPool)
+	return d
+}
+
+func (d *driver) step(op byte, size int) string {
+	size %= d.maxSize + 1
+
+	if err := d.verify(); err != "" {
+		return err
+	}
+
+	if len(d.held) >= maxHeld {
+		op = 6 // release
+	}
+	var b *bytepool.Bytes
+	switch op % 8 {
+	case 0, 1:
+		b = d.pool.GetGrown(size)
+		if len(b.B) != 0 || cap(b.B) < size {
+			return fmt.Sprintf("GetGrown(%v) len %v cap %v", size, len(b.B), cap(b.B))
+		}
+		b.B = b.B[:size]
+	case 2, 3:
+		b = d.pool.GetFilled(size)
+		if len(b.B) != size {
+			return fmt.Sprintf("GetFilled(%v) len %v", size, len(b.B))
+		}
+	case 4:
+		if d.pooler == nil {
+			return ""
+		}
+		b = d.pooler.Get()
+		if len(b.B) != 0 {
+			return fmt.Sprintf("Get len %v", len(b.B))
+		}
+		b.B = append(b.B, make([]byte, size)...)
+	case 5:
+		if d.sized == nil {
+			return ""
+		}
+		o := d.other.GetFilled(size)
+		if op%16 < 8 {
+			d.sized.Put(o)
+		} else {
+			d.sized.Adopt(o)
+			o.Release()
+		}
+		return ""
+	default:
+		if len(d.held) > 0 {
+			i := int(op) % len(d.held)
+			d.held[i].b.Release()
+			d.held[i] = d.held[len(d.held)-1]
+			d.held = d.held[:len(d.held)-1]
+		}
+		return ""
+	}
+
+	d.next++
+	h := held{b: b, v: d.tag*maxHeld*2 + d.next%(maxHeld*2)} // spare values as held shrinks.
+	fill(b, h.v)
+	d.held = append(d.held, h)
+	return ""
+}
+
+func (d *driver) verify() string {
+	for _, h := range d.held {
+		if !filled(h.b, h.v) {
+			return "held Bytes modified by another"
+		}
+	}
+	return ""
+}
+
+func (d *driver) releaseAll() {
+	for _, h := range d.held {
+		h.b.Release()
+	}
+	d.held = nil
+}
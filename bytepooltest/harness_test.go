@@ -0,0 +1,61 @@
+package bytepooltest_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+	"github.com/graxinc/bytepool/bytepooltest"
+)
+
+func TestCheckHarness(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sync", func(t *testing.T) {
+		bytepooltest.CheckHarness(t, bytepool.NewSync(), bytepooltest.HarnessOptions{})
+	})
+	t.Run("bucket", func(t *testing.T) {
+		pool := bytepool.NewBucket(1, 512)
+		bytepooltest.CheckHarness(t, pool, bytepooltest.HarnessOptions{
+			Counters: func() []uint64 {
+				s := pool.Stats()
+				return []uint64{s.Gets, s.Puts, s.Hits, s.Misses, s.Overs}
+			},
+		})
+	})
+	t.Run("bucket_pooler", func(t *testing.T) {
+		pool := bytepool.NewBucket(1, 512).Pooler(bytepool.BucketPoolerOptions{})
+		bytepooltest.CheckHarness(t, pool, bytepooltest.HarnessOptions{})
+	})
+	t.Run("bucket_retained", func(t *testing.T) {
+		pool := bytepool.NewBucketOptions(bytepool.Pow2Sizes(1, 512), bytepool.BucketPoolOptions{MaxRetained: 8})
+		bytepooltest.CheckHarness(t, pool, bytepooltest.HarnessOptions{})
+	})
+}
+
+func TestCheckHarness_decreasing(t *testing.T) {
+	t.Parallel()
+
+	var n uint64 = 1 << 20
+	ft := &fatalTB{TB: t}
+	done := make(chan struct{})
+	go func() { // Fatal calls Goexit.
+		defer close(done)
+		bytepooltest.CheckHarness(ft, bytepool.NewSync(), bytepooltest.HarnessOptions{
+			Counters: func() []uint64 {
+				n--
+				return []uint64{n}
+			},
+		})
+	}()
+	<-done
+	if !ft.failed {
+		t.Fatal("expected failure")
+	}
+}
+
+func FuzzCheckOps(f *testing.F) {
+	f.Add([]byte{0, 0, 10, 2, 1, 0, 6, 0, 0, 4, 0, 3, 5, 0, 7, 13, 0, 0})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		bytepooltest.CheckOps(t, bytepool.NewBucket(1, 1<<12).Pooler(bytepool.BucketPoolerOptions{}), ops)
+	})
+}
@@ -0,0 +1,72 @@
+package bytepool
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// Adapts codecs with the EncodeAll/DecodeAll "dst []byte" pattern, such as zstd, so dst is
+// borrowed from a pool with a size estimate from previous calls. Output the codec grew beyond
+// dst is kept in the returned Bytes, so it returns to the pool on Release.
+//
+//	codec := bytepool.NewCodec(pool, enc.EncodeAll, dec.DecodeAll)
+type Codec struct {
+	pool   SizedPooler
+	encode func(src, dst []byte) []byte
+	decode func(src, dst []byte) ([]byte, error)
+
+	encodeRatio ratio
+	decodeRatio ratio
+}
+
+// Either of encode or decode can be nil if unused. Codecs with (dst, src) parameter order,
+// such as snappy, need wrapping.
+func NewCodec(p SizedPooler, encode func(src, dst []byte) []byte, decode func(src, dst []byte) ([]byte, error)) *Codec {
+	return &Codec{pool: p, encode: encode, decode: decode}
+}
+
+// Encoded src, which the caller must Release.
+func (c *Codec) Encode(src []byte) *Bytes {
+	b := c.pool.GetGrown(c.encodeRatio.estimate(len(src)))
+	b.B = c.encode(src, b.B[:0]) // if grown by the codec, the larger array is kept.
+	c.encodeRatio.observe(len(src), len(b.B))
+	return b
+}
+
+// Decoded src, which the caller must Release.
+func (c *Codec) Decode(src []byte) (*Bytes, error) {
+	b := c.pool.GetGrown(c.decodeRatio.estimate(len(src)))
+	out, err := c.decode(src, b.B[:0])
+	if err != nil {
+		b.Release()
+		return nil, err
+	}
+	b.B = out // if grown by the codec, the larger array is kept.
+	c.decodeRatio.observe(len(src), len(b.B))
+	return b, nil
+}
+
+// Recent output to input length ratio, as float64 bits. Zero until observed.
+type ratio struct {
+	bits atomic.Uint64
+}
+
+func (r *ratio) estimate(inLen int) int {
+	v := math.Float64frombits(r.bits.Load())
+	if v == 0 {
+		return inLen
+	}
+	return int(float64(inLen)*v*1.1) + 64 // headroom to avoid the codec growing.
+}
+
+func (r *ratio) observe(inLen, outLen int) {
+	if inLen == 0 {
+		return
+	}
+	obs := float64(outLen) / float64(inLen)
+	old := math.Float64frombits(r.bits.Load())
+	if old != 0 {
+		obs = max(obs, old*0.9+obs*0.1) // rises fast, falls slowly.
+	}
+	r.bits.Store(math.Float64bits(obs)) // racing stores are fine for an estimate.
+}
@@ -0,0 +1,75 @@
+package bytepool_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+// doubles each byte, growing dst like a codec would.
+func doubleEncode(src, dst []byte) []byte {
+	for _, v := range src {
+		dst = append(dst, v, v)
+	}
+	return dst
+}
+
+func halveDecode(src, dst []byte) ([]byte, error) {
+	if len(src)%2 != 0 {
+		return nil, errors.New("odd")
+	}
+	for i := 0; i < len(src); i += 2 {
+		dst = append(dst, src[i])
+	}
+	return dst, nil
+}
+
+func TestCodec(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(8, 1<<16)
+	codec := bytepool.NewCodec(pool, doubleEncode, halveDecode)
+
+	src := []byte("0123456789abcdef")
+	for i := range 5 {
+		enc := codec.Encode(src)
+		diffFatal(t, 2*len(src), len(enc.B))
+
+		dec, err := codec.Decode(enc.B)
+		if err != nil {
+			t.Fatal(err)
+		}
+		diffFatal(t, src, dec.B)
+
+		if i > 0 && cap(enc.B) > 128 { // estimate learned, from the bucket
+			t.Fatal(cap(enc.B))
+		}
+		enc.Release()
+		dec.Release()
+	}
+
+	if _, err := codec.Decode([]byte("odd")); err == nil {
+		t.Fatal("expected error")
+	}
+
+	st := pool.Stats()
+	diffFatal(t, st.Gets, st.Puts)
+}
+
+func TestCodec_estimate(t *testing.T) {
+	t.Parallel()
+
+	var fits []bool
+	encode := func(src, dst []byte) []byte {
+		fits = append(fits, cap(dst) >= 2*len(src))
+		return doubleEncode(src, dst)
+	}
+	codec := bytepool.NewCodec(bytepool.NewBucket(8, 1<<16), encode, nil)
+
+	src := make([]byte, 1500)
+	for range 3 {
+		codec.Encode(src).Release()
+	}
+	diffFatal(t, []bool{false, true, true}, fits)
+}
@@ -0,0 +1,98 @@
+package bytepool
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Reuses gzip and flate writers by level, with compressed output appended into pooled Bytes.
+// Compressor state dwarfs most payloads, so reusing it matters more than the output buffer.
+type Compressors struct {
+	pool  SizedPooler
+	gzip  [levels]sync.Pool
+	flate [levels]sync.Pool
+}
+
+// levels from flate.HuffmanOnly (-2) to flate.BestCompression (9).
+const levels = flate.BestCompression - flate.HuffmanOnly + 1
+
+// Output Bytes come from p.
+func NewCompressors(p SizedPooler) *Compressors {
+	return &Compressors{pool: p}
+}
+
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// Writes compressed output into pooled Bytes, obtained with Close.
+type CompressWriter struct {
+	c     compressor
+	out   *Appender
+	cache *sync.Pool // c returned on Close.
+}
+
+// Writer for gzip at level, as gzip.NewWriterLevel.
+func (c *Compressors) Gzip(level int) (*CompressWriter, error) {
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		return nil, fmt.Errorf("gzip: invalid compression level: %d", level)
+	}
+	return c.writer(&c.gzip[level-flate.HuffmanOnly], func(w io.Writer) compressor {
+		z, _ := gzip.NewWriterLevel(w, level) // level checked.
+		return z
+	}), nil
+}
+
+// Writer for flate at level, as flate.NewWriter.
+func (c *Compressors) Flate(level int) (*CompressWriter, error) {
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		return nil, fmt.Errorf("flate: invalid compression level %d: want value in range [-2, 9]", level)
+	}
+	return c.writer(&c.flate[level-flate.HuffmanOnly], func(w io.Writer) compressor {
+		z, _ := flate.NewWriter(w, level) // level checked.
+		return z
+	}), nil
+}
+
+func (c *Compressors) writer(cache *sync.Pool, newCompressor func(io.Writer) compressor) *CompressWriter {
+	out := NewAppender(c.pool)
+	z, _ := cache.Get().(compressor)
+	if z == nil {
+		z = newCompressor(out)
+	} else {
+		z.Reset(out)
+	}
+	return &CompressWriter{c: z, out: out, cache: cache}
+}
+
+func (w *CompressWriter) Write(p []byte) (int, error) {
+	return w.c.Write(p)
+}
+
+func (w *CompressWriter) Flush() error {
+	return w.c.Flush()
+}
+
+// Finishes the stream and returns its output, which the caller must Release.
+// The writer is reused so must not be used after.
+func (w *CompressWriter) Close() (*Bytes, error) {
+	err := w.c.Close()
+	w.c.Reset(io.Discard) // drop reference to out.
+	w.cache.Put(w.c)
+	w.c = nil
+
+	out := w.out.Take()
+	if err != nil {
+		out.Release()
+		return nil, err
+	}
+	if out == nil {
+		out = w.out.p.GetGrown(0)
+	}
+	return out, nil
+}
@@ -0,0 +1,79 @@
+package bytepool_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestCompressors(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(64, 1<<16)
+	c := bytepool.NewCompressors(pool)
+	payload := []byte(strings.Repeat("compress me ", 1000))
+
+	for range 3 { // reused
+		for _, level := range []int{flate.HuffmanOnly, flate.DefaultCompression, flate.BestSpeed, flate.BestCompression} {
+			w, err := c.Gzip(level)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := w.Write(payload); err != nil {
+				t.Fatal(err)
+			}
+			out, err := w.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := gzip.NewReader(bytes.NewReader(out.B))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			diffFatal(t, payload, got)
+			out.Release()
+
+			w, err = c.Flate(level)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := w.Write(payload); err != nil {
+				t.Fatal(err)
+			}
+			out, err = w.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err = io.ReadAll(flate.NewReader(bytes.NewReader(out.B)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			diffFatal(t, payload, got)
+			out.Release()
+		}
+	}
+
+	st := pool.Stats()
+	diffFatal(t, st.Gets, st.Puts)
+}
+
+func TestCompressors_invalidLevel(t *testing.T) {
+	t.Parallel()
+
+	c := bytepool.NewCompressors(bytepool.NewSync())
+	if _, err := c.Gzip(10); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := c.Flate(-3); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,35 @@
+package bytepool
+
+// Concatenation of parts in one exactly sized Bytes from p.
+// Call Release on the returned Bytes to return it to the pool.
+func Concat(p SizedPooler, parts ...[]byte) *Bytes {
+	var n int
+	for _, part := range parts {
+		n += len(part)
+	}
+	b := p.GetGrown(n)
+	for _, part := range parts {
+		b.B = append(b.B, part...)
+	}
+	return b
+}
+
+// Same as bytes.Join into one exactly sized Bytes from p.
+// Call Release on the returned Bytes to return it to the pool.
+func Join(p SizedPooler, parts [][]byte, sep []byte) *Bytes {
+	var n int
+	for _, part := range parts {
+		n += len(part)
+	}
+	if len(parts) > 1 {
+		n += len(sep) * (len(parts) - 1)
+	}
+	b := p.GetGrown(n)
+	for i, part := range parts {
+		if i > 0 {
+			b.B = append(b.B, sep...)
+		}
+		b.B = append(b.B, part...)
+	}
+	return b
+}
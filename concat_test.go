@@ -0,0 +1,37 @@
+package bytepool_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/graxinc/bytepool"
+)
+
+func TestConcatJoin(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(2, 64)
+
+	cases := [][][]byte{
+		nil,
+		{[]byte("a")},
+		{[]byte("a"), nil, []byte("bc")},
+		{[]byte("abc"), []byte("de"), []byte("f")},
+	}
+	for _, parts := range cases {
+		t.Run(fmt.Sprint(parts), func(t *testing.T) {
+			b := bytepool.Concat(pool, parts...)
+			diffFatal(t, bytes.Join(parts, nil), b.B, cmpopts.EquateEmpty())
+			b.Release()
+
+			b = bytepool.Join(pool, parts, []byte(", "))
+			diffFatal(t, bytes.Join(parts, []byte(", ")), b.B, cmpopts.EquateEmpty())
+			b.Release()
+		})
+	}
+
+	s := pool.Stats()
+	diffFatal(t, uint64(0), s.Overs)
+}
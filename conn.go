@@ -0,0 +1,86 @@
+package bytepool
+
+import (
+	"sync/atomic"
+)
+
+type ConnPoolerOptions struct {
+	Pooler BucketPoolerOptions
+	Local  LocalOptions
+}
+
+// Pooler for one connection over a shared BucketPool, with its own default size and a
+// LocalPool cache, as sizes often differ more between connections than within one.
+// Close when the connection closes, such as from http.Server.ConnState or after serving
+// a connection from a custom accept loop.
+type ConnPooler struct {
+	pooler *BucketPooler // only chooses the default size, Bytes are in local.
+	local  *LocalPool
+	closed atomic.Bool
+}
+
+func (p *BucketPool) ConnPooler(o ConnPoolerOptions) *ConnPooler {
+	return &ConnPooler{
+		pooler: p.Pooler(o.Pooler),
+		local:  p.Local(o.Local),
+	}
+}
+
+// Bytes with zero length and the connection's default capacity.
+func (c *ConnPooler) Get() *Bytes {
+	return c.GetGrown(c.pooler.defaultSize())
+}
+
+func (c *ConnPooler) GetGrown(cp int) *Bytes {
+	b := c.local.GetGrown(cp)
+	b.pool = c
+	return b
+}
+
+func (c *ConnPooler) GetFilled(length int) *Bytes {
+	b := c.GetGrown(length)
+	b.B = b.B[:length]
+	return b
+}
+
+func (c *ConnPooler) Put(b *Bytes) {
+	if b != nil {
+		adopt(b, c)
+		b.Release()
+	}
+}
+
+func (c *ConnPooler) Adopt(b *Bytes) {
+	if b != nil {
+		adopt(b, c)
+	}
+}
+
+// Spills the local cache to the shared pool. Gets and Releases after Close, or racing it, also
+// go to the shared pool.
+func (c *ConnPooler) Close() {
+	c.closed.Store(true)
+	c.local.close()
+}
+
+type ConnPoolerStats struct {
+	DefaultSize int
+	Local       LocalPoolStats
+}
+
+func (c *ConnPooler) Stats() ConnPoolerStats {
+	return ConnPoolerStats{
+		DefaultSize: c.pooler.defaultSize(),
+		Local:       c.local.Stats(),
+	}
+}
+
+func (c *ConnPooler) put(b *Bytes) {
+	if b == nil {
+		return
+	}
+	if !c.closed.Load() {
+		c.pooler.observe(len(b.B))
+	}
+	c.local.put(b) // to the shared pool once closed.
+}
@@ -0,0 +1,75 @@
+package bytepool_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestConnPooler(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8, 64}, bytepool.BucketPoolOptions{MaxRetained: 100})
+	o := bytepool.ConnPoolerOptions{Pooler: bytepool.BucketPoolerOptions{ChooseInc: 10}}
+	small, large := pool.ConnPooler(o), pool.ConnPooler(o)
+	var _ bytepool.Pool = small
+
+	release := func(p *bytepool.ConnPooler, l int) {
+		for range 100 {
+			b := p.Get()
+			b.B = append(b.B, make([]byte, l)...)
+			b.Release()
+		}
+	}
+	release(small, 8)
+	release(large, 64)
+	diffFatal(t, 8, small.Stats().DefaultSize)
+	diffFatal(t, 64, large.Stats().DefaultSize)
+	diffFatal(t, 64, cap(large.Get().B))
+
+	if s := small.Stats(); s.Local.Retained == 0 || s.Local.Hits == 0 {
+		t.Fatal(s)
+	}
+
+	b := small.GetGrown(60)
+	small.Close()
+	diffFatal(t, 0, small.Stats().Local.Retained)
+
+	pooled := pool.Stats().Pooled
+	b.Release() // to the shared pool after Close
+	diffFatal(t, pooled+1, pool.Stats().Pooled)
+	diffFatal(t, 0, small.Stats().Local.Retained)
+}
+
+func TestConnPooler_closeRacingRelease(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{MaxRetained: 1000})
+	for range 50 {
+		c := pool.ConnPooler(bytepool.ConnPoolerOptions{})
+		var held []*bytepool.Bytes
+		for range 8 {
+			held = append(held, c.GetGrown(8))
+		}
+
+		var wg sync.WaitGroup
+		for _, b := range held {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				b.Release()
+			}()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Close()
+		}()
+		wg.Wait()
+		diffFatal(t, 0, c.Stats().Local.Retained) // none stranded locally.
+		c.Get().Release()
+		diffFatal(t, 0, c.Stats().Local.Retained)
+	}
+}
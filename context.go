@@ -0,0 +1,31 @@
+package bytepool
+
+import (
+	"context"
+	"sync"
+)
+
+type poolerKey struct{}
+
+var fallbackPooler = sync.OnceValue(func() Pooler {
+	return NewDynamic()
+})
+
+// The Pooler used by PoolerFromContext when ctx carries none, created with NewDynamic
+// on first use as sizes are unknown.
+func FallbackPooler() Pooler {
+	return fallbackPooler()
+}
+
+// Context carrying p, such as a per request or per tenant pooler set by middleware.
+func WithPooler(ctx context.Context, p Pooler) context.Context {
+	return context.WithValue(ctx, poolerKey{}, p)
+}
+
+// The Pooler from WithPooler, otherwise FallbackPooler.
+func PoolerFromContext(ctx context.Context) Pooler {
+	if p, ok := ctx.Value(poolerKey{}).(Pooler); ok && p != nil {
+		return p
+	}
+	return FallbackPooler()
+}
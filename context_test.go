@@ -0,0 +1,38 @@
+package bytepool_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestPoolerFromContext(t *testing.T) {
+	t.Parallel()
+
+	fallback := bytepool.PoolerFromContext(context.Background())
+	if fallback == nil || fallback != bytepool.FallbackPooler() {
+		t.Fatal(fallback)
+	}
+
+	pooler := bytepool.NewBucket(8, 64).Pooler(bytepool.BucketPoolerOptions{})
+	ctx := bytepool.WithPooler(context.Background(), pooler)
+	if got := bytepool.PoolerFromContext(ctx); got != pooler {
+		t.Fatal(got)
+	}
+
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if got := bytepool.PoolerFromContext(child); got != pooler {
+		t.Fatal(got)
+	}
+
+	ctx = bytepool.WithPooler(ctx, nil)
+	if got := bytepool.PoolerFromContext(ctx); got != fallback {
+		t.Fatal(got)
+	}
+
+	b := bytepool.PoolerFromContext(ctx).GetGrown(10)
+	diffFatal(t, true, cap(b.B) >= 10)
+	b.Release()
+}
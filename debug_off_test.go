@@ -0,0 +1,8 @@
+//go:build !bytepool_debug
+
+package bytepool_test
+
+import "testing"
+
+// Skips tests asserting released contents with bytepool_debug, see debug_test.go.
+func requireNoDebug(*testing.T) {}
@@ -0,0 +1,36 @@
+//go:build bytepool_debug
+
+package bytepool
+
+import (
+	"bytes"
+	"unsafe"
+)
+
+const debugPoison = 0xdb
+
+// Validates UnsafeString views and poisons released Bytes so use after Release is visible.
+type debugState struct {
+	viewPtr *byte
+	view    []byte // copy of B when viewed, nil otherwise.
+}
+
+func (d *debugState) viewed(b []byte) {
+	d.viewPtr = unsafe.SliceData(b)
+	d.view = append([]byte{}, b...)
+}
+
+func (d *debugState) released(b []byte) {
+	if d.view != nil && d.viewPtr == unsafe.SliceData(b) && len(d.view) <= cap(b) {
+		if !bytes.Equal(d.view, b[:len(d.view)]) {
+			panic("Bytes modified while UnsafeString view held")
+		}
+	}
+	d.viewPtr = nil
+	d.view = nil
+
+	b = b[:cap(b)]
+	for i := range b {
+		b[i] = debugPoison
+	}
+}
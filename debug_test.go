@@ -0,0 +1,42 @@
+//go:build bytepool_debug
+
+package bytepool_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+// Skips tests asserting contents of released Bytes, which debug builds poison.
+func requireNoDebug(t *testing.T) {
+	t.Helper()
+	t.Skip("asserts released contents, poisoned with bytepool_debug")
+}
+
+func TestBytes_UnsafeString_debug(t *testing.T) {
+	t.Parallel()
+
+	t.Run("poisons", func(t *testing.T) {
+		b := bytepool.NewSync().GetGrown(3)
+		b.B = append(b.B, "abc"...)
+		s := b.UnsafeString()
+		b.Release()
+		if s == "abc" {
+			t.Fatal("not poisoned")
+		}
+	})
+	t.Run("modified", func(t *testing.T) {
+		b := bytepool.NewSync().GetGrown(3)
+		b.B = append(b.B, "abc"...)
+		_ = b.UnsafeString()
+		b.B[1] = 'x'
+
+		defer func() {
+			if recover() == nil {
+				t.Fatal("no panic")
+			}
+		}()
+		b.Release()
+	})
+}
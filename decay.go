@@ -0,0 +1,48 @@
+package bytepool
+
+import (
+	"math"
+	"time"
+)
+
+// Decays the put counts a BucketPooler chooses its default bin from, trading responsiveness
+// to shifts in sizes against stability. Called concurrently.
+type Decayer interface {
+	// Returns the count to keep, elapsed being the time since the previous decay.
+	Decay(puts int64, elapsed time.Duration) int64
+}
+
+type DecayerFunc func(puts int64, elapsed time.Duration) int64
+
+func (f DecayerFunc) Decay(puts int64, elapsed time.Duration) int64 {
+	return f(puts, elapsed)
+}
+
+// Keeps factor of the count each choice, regardless of time. Factor <= 0 defaults to 0.5.
+func MultiplicativeDecay(factor float64) Decayer {
+	if factor <= 0 {
+		factor = 0.5
+	}
+	return DecayerFunc(func(puts int64, _ time.Duration) int64 {
+		return int64(math.RoundToEven(float64(puts) * factor))
+	})
+}
+
+// Drops all counts each choice, so only the last ChooseInc puts count. Most responsive, suiting
+// traffic that shifts wholesale.
+func WindowDecay() Decayer {
+	return DecayerFunc(func(int64, time.Duration) int64 {
+		return 0
+	})
+}
+
+// Halves counts per halfLife of elapsed time, so bursts fade by time rather than by put count,
+// suiting bursty traffic. Panics if halfLife <= 0.
+func HalfLifeDecay(halfLife time.Duration) Decayer {
+	if halfLife <= 0 {
+		panic("halfLife <= 0")
+	}
+	return DecayerFunc(func(puts int64, elapsed time.Duration) int64 {
+		return int64(math.RoundToEven(float64(puts) * math.Exp2(-float64(elapsed)/float64(halfLife))))
+	})
+}
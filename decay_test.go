@@ -0,0 +1,42 @@
+package bytepool_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestDecayers(t *testing.T) {
+	t.Parallel()
+
+	diffFatal(t, int64(50), bytepool.MultiplicativeDecay(0).Decay(100, time.Hour))
+	diffFatal(t, int64(90), bytepool.MultiplicativeDecay(0.9).Decay(100, 0))
+	diffFatal(t, int64(0), bytepool.WindowDecay().Decay(100, 0))
+
+	half := bytepool.HalfLifeDecay(time.Second)
+	diffFatal(t, int64(100), half.Decay(100, 0))
+	diffFatal(t, int64(50), half.Decay(100, time.Second))
+	diffFatal(t, int64(25), half.Decay(100, 2*time.Second))
+}
+
+func TestBucketPooler_decayer(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucket(8, 64)
+	pooler := pool.Pooler(bytepool.BucketPoolerOptions{ChooseInc: 10, Decayer: bytepool.WindowDecay()})
+
+	release := func(l, n int) {
+		for range n {
+			b := pooler.Get()
+			b.B = append(b.B, make([]byte, l)...)
+			b.Release()
+		}
+	}
+	release(64, 1000)
+	diffFatal(t, 64, pooler.Stats().DefaultSize)
+
+	release(8, 10) // one window is enough, as nothing carries over.
+	diffFatal(t, 8, pooler.Stats().DefaultSize)
+}
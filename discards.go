@@ -0,0 +1,91 @@
+package bytepool
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Why a BucketPool discarded released or retained Bytes.
+type DiscardReason int
+
+const (
+	DiscardOver   DiscardReason = iota // over MaxSize, bypassed or capped by GetGrownAtMost.
+	DiscardFull                        // the bucket held MaxRetained.
+	DiscardBudget                      // dropped over MemoryLimit Hard or evicted over Soft.
+	DiscardTrim                        // by TrimInterval, ApplyOptions or Drain.
+	discardReasons
+)
+
+func (r DiscardReason) String() string {
+	switch r {
+	case DiscardOver:
+		return "over"
+	case DiscardFull:
+		return "full"
+	case DiscardBudget:
+		return "budget"
+	case DiscardTrim:
+		return "trim"
+	}
+	return "unknown"
+}
+
+// Discards of one reason over an interval of SubscribeDiscards.
+type DiscardEvent struct {
+	Reason DiscardReason
+	Count  uint64
+	Bytes  uint64 // by capacity.
+}
+
+// Cumulative, not cleared by ResetStats so subscribers see every discard.
+type discardCounts [discardReasons]struct {
+	count atomic.Uint64
+	bytes atomic.Uint64
+}
+
+// Nil does nothing.
+func (d *discardCounts) add(r DiscardReason, n, bytes int) {
+	if d == nil {
+		return
+	}
+	d[r].count.Add(uint64(n))
+	d[r].bytes.Add(uint64(bytes))
+}
+
+// Calls fn every interval with the pool's discards since the previous call, one event per
+// reason with any, skipping intervals without discards. Such as to shrink application caches
+// as the pool comes under pressure. fn is called from its own goroutine until cancel.
+func (p *BucketPool) SubscribeDiscards(interval time.Duration, fn func([]DiscardEvent)) (cancel func()) {
+	if interval <= 0 {
+		panic("interval <= 0")
+	}
+	var prev [discardReasons][2]uint64
+	load := func() (cur [discardReasons][2]uint64) {
+		for r := range p.discards {
+			cur[r] = [2]uint64{p.discards[r].count.Load(), p.discards[r].bytes.Load()}
+		}
+		return cur
+	}
+	prev = load()
+
+	stop := make(chan struct{})
+	go runEvery(interval, stop, func() {
+		cur := load()
+		var events []DiscardEvent
+		for r := range cur {
+			if n := cur[r][0] - prev[r][0]; n > 0 {
+				events = append(events, DiscardEvent{Reason: DiscardReason(r), Count: n, Bytes: cur[r][1] - prev[r][1]})
+			}
+		}
+		prev = cur
+		if events != nil {
+			fn(events)
+		}
+	})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(stop) })
+	}
+}
@@ -0,0 +1,54 @@
+package bytepool_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_SubscribeDiscards(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8, 16}, bytepool.BucketPoolOptions{
+		MaxRetained: 1,
+		MemoryLimit: bytepool.MemoryLimit{Hard: 40},
+	})
+
+	events := make(chan []bytepool.DiscardEvent, 10)
+	cancel := pool.SubscribeDiscards(time.Millisecond, func(e []bytepool.DiscardEvent) { events <- e })
+	defer cancel()
+
+	b1, b2, b3, b4 := pool.GetGrown(8), pool.GetGrown(8), pool.GetGrown(16), pool.GetGrown(16)
+	b1.Release() // over Hard with 48 outstanding.
+	b2.Release()
+	b3.Release()
+	b4.Release() // full.
+	pool.GetGrown(17).Release()
+	diffFatal(t, 2, pool.Drain())
+
+	want := map[bytepool.DiscardReason]bytepool.DiscardEvent{
+		bytepool.DiscardOver:   {Reason: bytepool.DiscardOver, Count: 1, Bytes: 17},
+		bytepool.DiscardFull:   {Reason: bytepool.DiscardFull, Count: 1, Bytes: 16},
+		bytepool.DiscardBudget: {Reason: bytepool.DiscardBudget, Count: 1, Bytes: 8},
+		bytepool.DiscardTrim:   {Reason: bytepool.DiscardTrim, Count: 2, Bytes: 24},
+	}
+	got := make(map[bytepool.DiscardReason]bytepool.DiscardEvent)
+	timeout := time.After(10 * time.Second)
+	for len(got) < len(want) {
+		select {
+		case es := <-events:
+			for _, e := range es {
+				g := got[e.Reason]
+				g.Reason = e.Reason
+				g.Count += e.Count
+				g.Bytes += e.Bytes
+				got[e.Reason] = g
+			}
+		case <-timeout:
+			t.Fatal("missing events", got)
+		}
+	}
+	diffFatal(t, want, got)
+	diffFatal(t, "budget", bytepool.DiscardBudget.String())
+}
@@ -0,0 +1,80 @@
+package bytepool
+
+// Two pooled Bytes swapping between fill and drain, for pipelined IO where one is written out
+// while the next is filled. The filler uses Fill and Swap, the drainer calls Drained when done
+// with the Bytes from Swap, possibly from another goroutine.
+//
+//	for more {
+//		db.Fill().B = append(db.Fill().B, data...)
+//		d := db.Swap()
+//		go func() { w.Write(d.B); db.Drained() }()
+//	}
+//	db.Close()
+type DoubleBuffer struct {
+	pool SizedPooler
+	size int
+
+	fill     *Bytes
+	draining *Bytes
+	spare    *Bytes        // drained, next fill.
+	idle     chan struct{} // holds a token when nothing is draining.
+	closed   bool
+}
+
+// Bytes come from p with capacity of at least size.
+func NewDoubleBuffer(p SizedPooler, size int) *DoubleBuffer {
+	d := &DoubleBuffer{
+		pool: p,
+		size: size,
+		idle: make(chan struct{}, 1),
+	}
+	d.idle <- struct{}{}
+	return d
+}
+
+// Bytes being filled, owned by the DoubleBuffer.
+func (d *DoubleBuffer) Fill() *Bytes {
+	if d.fill == nil {
+		d.fill = d.pool.GetGrown(d.size)
+	}
+	return d.fill
+}
+
+// Waits for the previous drain, then returns the filled Bytes for draining and starts filling
+// the other, emptied. The returned Bytes is valid until Drained.
+func (d *DoubleBuffer) Swap() *Bytes {
+	filled := d.Fill()
+	<-d.idle
+
+	d.draining = filled
+	d.fill = d.spare
+	d.spare = nil
+	if d.fill != nil {
+		d.fill.B = d.fill.B[:0]
+	}
+	return filled
+}
+
+// Marks the Bytes from Swap drained. Panics if nothing is draining.
+func (d *DoubleBuffer) Drained() {
+	if d.draining == nil {
+		panic("Drained without Swap")
+	}
+	d.spare = d.draining
+	d.draining = nil
+	d.idle <- struct{}{}
+}
+
+// Waits for any drain, then releases both Bytes. The DoubleBuffer must not be used after,
+// though closing again does nothing.
+func (d *DoubleBuffer) Close() {
+	if d.closed {
+		return
+	}
+	d.closed = true
+	<-d.idle
+	d.fill.Release()
+	d.spare.Release()
+	d.fill = nil
+	d.spare = nil
+}
@@ -0,0 +1,58 @@
+package bytepool_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestDoubleBuffer(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucket(8, 64)
+	db := bytepool.NewDoubleBuffer(pool, 16)
+
+	var out bytes.Buffer
+	drained := make(chan []byte, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for b := range drained {
+			out.Write(b)
+			db.Drained()
+		}
+	}()
+
+	var want bytes.Buffer
+	for i := range 20 {
+		fill := db.Fill()
+		for range 10 {
+			fill.B = append(fill.B, byte(i))
+		}
+		want.Write(fill.B)
+		drained <- db.Swap().B
+	}
+	close(drained)
+	<-done
+	db.Close()
+	db.Close() // does nothing, rather than waiting on a drain.
+
+	diffFatal(t, want.Bytes(), out.Bytes())
+
+	st := pool.Stats()
+	diffFatal(t, uint64(2), st.Gets) // only two Bytes swapping
+	diffFatal(t, uint64(2), st.Puts)
+}
+
+func TestDoubleBuffer_drainedWithoutSwap(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	bytepool.NewDoubleBuffer(bytepool.NewSync(), 8).Drained()
+}
@@ -0,0 +1,72 @@
+package bytepool
+
+import (
+	"sync/atomic"
+)
+
+// Wraps p so every nth Release is dropped rather than returned, simulating sync.Pool
+// drops during GC deterministically. Intended for testing code that must be robust to
+// cold pools without forcing GC in a loop. Panics if n < 1.
+func DropEvery(p Pool, n int) Pool {
+	return newDropPool(p, n)
+}
+
+// DropEvery for a SizedPool such as BucketPool.
+func DropEverySized(p SizedPool, n int) SizedPool {
+	return newDropPool(p, n)
+}
+
+type dropPool struct {
+	p        SizedPool
+	n        uint64
+	releases atomic.Uint64
+}
+
+func newDropPool(p SizedPool, n int) *dropPool {
+	if n < 1 {
+		panic("n must be positive")
+	}
+	return &dropPool{p: p, n: uint64(n)}
+}
+
+// Only valid when p is a Pool, guarded by DropEvery.
+func (d *dropPool) Get() *Bytes {
+	b := d.p.(Pool).Get()
+	b.pool = d
+	return b
+}
+
+func (d *dropPool) GetGrown(c int) *Bytes {
+	b := d.p.GetGrown(c)
+	b.pool = d
+	return b
+}
+
+func (d *dropPool) GetFilled(length int) *Bytes {
+	b := d.p.GetFilled(length)
+	b.pool = d
+	return b
+}
+
+func (d *dropPool) Put(b *Bytes) {
+	if b != nil {
+		adopt(b, d)
+		b.Release()
+	}
+}
+
+func (d *dropPool) Adopt(b *Bytes) {
+	if b != nil {
+		adopt(b, d)
+	}
+}
+
+func (d *dropPool) put(b *Bytes) {
+	if b == nil {
+		return
+	}
+	if d.releases.Add(1)%d.n == 0 {
+		return // left for GC, as sync.Pool would.
+	}
+	d.p.Put(b)
+}
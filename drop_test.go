@@ -0,0 +1,51 @@
+package bytepool_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestDropEvery(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{MaxRetained: 10})
+	drop := bytepool.DropEverySized(pool, 3)
+
+	for range 6 {
+		drop.GetGrown(8).Release()
+	}
+
+	s := pool.Stats()
+	diffFatal(t, uint64(6), s.Gets)
+	diffFatal(t, uint64(4), s.Puts)
+	diffFatal(t, uint64(2), s.Misses) // after the drops
+}
+
+func TestDropEvery_pool(t *testing.T) {
+	t.Parallel()
+
+	drop := bytepool.DropEvery(bytepool.NewSync(), 1)
+
+	for range 1000 {
+		b1 := drop.Get()
+		b1.Release()
+		b2 := drop.Get()
+		b2.Release()
+		if b1 == b2 {
+			t.Fatal("reused")
+		}
+	}
+}
+
+func TestDropEvery_invalid(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	bytepool.DropEvery(bytepool.NewSync(), 0)
+}
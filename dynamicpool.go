@@ -21,6 +21,7 @@ const (
 type dynamicPool struct {
 	calls       [steps]uint64
 	calibrating uint64
+	frozen      uint32
 
 	defaultSize uint64
 	maxSize     uint64
@@ -32,7 +33,7 @@ type dynamicPool struct {
 
 // Continually tunes the Get allocation size and max Released size.
 // Suitable for variable sized Bytes, but at a cost.
-func NewDynamic() Pooler {
+func NewDynamic() Pool {
 	return new(dynamicPool)
 }
 
@@ -41,7 +42,9 @@ func (p *dynamicPool) Get() *Bytes {
 	if v == nil {
 		return makeSizedBytes(int(atomic.LoadUint64(&p.defaultSize)), p)
 	}
-	return v.(*Bytes)
+	b := v.(*Bytes)
+	b.pool = p // could have been Put from another pool.
+	return b
 }
 
 func (p *dynamicPool) GetGrown(c int) *Bytes {
@@ -56,15 +59,29 @@ func (p *dynamicPool) GetFilled(len int) *Bytes {
 	return b
 }
 
+func (p *dynamicPool) Put(b *Bytes) {
+	if b != nil {
+		adopt(b, p)
+		b.Release()
+	}
+}
+
+func (p *dynamicPool) Adopt(b *Bytes) {
+	if b != nil {
+		adopt(b, p)
+	}
+}
+
 func (p *dynamicPool) put(b *Bytes) {
 	if b == nil {
 		return
 	}
 
-	idx := index(len(b.B))
-
-	if atomic.AddUint64(&p.calls[idx], 1) > calibrateCallsThreshold {
-		p.calibrate()
+	if atomic.LoadUint32(&p.frozen) == 0 {
+		idx := index(len(b.B))
+		if atomic.AddUint64(&p.calls[idx], 1) > calibrateCallsThreshold {
+			p.calibrate()
+		}
 	}
 
 	maxSize := int(atomic.LoadUint64(&p.maxSize))
@@ -74,6 +91,14 @@ func (p *dynamicPool) put(b *Bytes) {
 	}
 }
 
+func (p *dynamicPool) Freeze() {
+	atomic.StoreUint32(&p.frozen, 1)
+}
+
+func (p *dynamicPool) Unfreeze() {
+	atomic.StoreUint32(&p.frozen, 0)
+}
+
 func (p *dynamicPool) calibrate() {
 	if !atomic.CompareAndSwapUint64(&p.calibrating, 0, 1) {
 		return
@@ -78,3 +78,26 @@ func allocNBytes(dst []byte, n int) []byte {
 	}
 	return append(dst, make([]byte, diff)...)
 }
+
+func TestDynamic_freeze(t *testing.T) {
+	t.Parallel()
+
+	p := bytepool.NewDynamic()
+	defaultSize := func() uint64 {
+		return p.(bytepool.MetricsReader).AppendMetrics(nil)[0].Value
+	}
+	release := func() {
+		for range 42001 { // calibrateCallsThreshold
+			b := p.GetFilled(1000)
+			b.Release()
+		}
+	}
+
+	p.(bytepool.Calibrator).Freeze()
+	release()
+	diffFatal(t, uint64(0), defaultSize())
+
+	p.(bytepool.Calibrator).Unfreeze()
+	release()
+	diffFatal(t, uint64(1024), defaultSize())
+}
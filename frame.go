@@ -0,0 +1,67 @@
+package bytepool
+
+import (
+	"io"
+)
+
+// Frames with a header reserve before the payload, so a header and payload are written from
+// one buffer, such as websocket or message broker frames.
+type FramePool struct {
+	pool    SizedPooler
+	reserve int
+}
+
+// headerReserve is the largest header, such as 14 for websocket.
+func NewFramePool(p SizedPooler, headerReserve int) *FramePool {
+	if headerReserve < 0 {
+		panic("headerReserve < 0")
+	}
+	return &FramePool{pool: p, reserve: headerReserve}
+}
+
+// Frame with a payload of payloadLen and no header.
+func (p *FramePool) GetFrame(payloadLen int) Frame {
+	return Frame{
+		b:       p.pool.GetFilled(p.reserve + payloadLen),
+		start:   p.reserve,
+		reserve: p.reserve,
+	}
+}
+
+// From FramePool. Do not use after WriteTo or Release.
+type Frame struct {
+	b       *Bytes // B is the reserve then the payload.
+	start   int    // of the header.
+	reserve int
+}
+
+// Header of length n directly before the payload, to be filled by the caller.
+// Replaces any previous header. Panics when over the header reserve.
+func (f *Frame) Header(n int) []byte {
+	if n < 0 || n > f.reserve {
+		panic("header over reserve")
+	}
+	f.start = f.reserve - n
+	return f.b.B[f.start:f.reserve]
+}
+
+func (f *Frame) Payload() []byte {
+	return f.b.B[f.reserve:]
+}
+
+// Header then payload.
+func (f *Frame) Bytes() []byte {
+	return f.b.B[f.start:]
+}
+
+// Writes the header and payload with one Write, then releases, including on errors.
+func (f *Frame) WriteTo(w io.Writer) (int64, error) {
+	defer f.Release()
+	n, err := w.Write(f.Bytes())
+	return int64(n), err
+}
+
+func (f *Frame) Release() {
+	f.b.Release()
+	f.b = nil
+}
@@ -0,0 +1,52 @@
+package bytepool_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestFramePool(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{16, 64}, bytepool.BucketPoolOptions{MaxRetained: 10})
+	frames := bytepool.NewFramePool(pool, 14)
+
+	f := frames.GetFrame(2)
+	diffFatal(t, 2, len(f.Payload()))
+	diffFatal(t, 2, len(f.Bytes())) // no header yet
+	copy(f.Payload(), "hi")
+
+	copy(f.Header(4), "long")
+	copy(f.Header(2), "ab") // replaces
+	diffFatal(t, "abhi", string(f.Bytes()))
+
+	var w bytes.Buffer
+	n, err := f.WriteTo(&w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, int64(4), n)
+	diffFatal(t, "abhi", w.String())
+
+	s := pool.Stats()
+	diffFatal(t, [2]int64{0, 1}, [2]int64{s.Outstanding, int64(s.Pooled)}) // released on write
+
+	f = frames.GetFrame(50) // 64 bucket
+	diffFatal(t, 50, len(f.Payload()))
+	if _, err := f.WriteTo(errWriter{}); err == nil {
+		t.Fatal("expected error")
+	}
+	diffFatal(t, int64(0), pool.Stats().Outstanding) // released on error
+
+	f = frames.GetFrame(1)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+		f.Release()
+	}()
+	f.Header(15)
+}
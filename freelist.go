@@ -0,0 +1,193 @@
+package bytepool
+
+import (
+	"sync"
+)
+
+// Bounded LIFO of Bytes, an alternative to sync.Pool that isn't cleared by GC.
+// LIFO so the most recently used (cache warm) Bytes are reused first.
+type freeList struct {
+	mu     sync.Mutex
+	ring   []*Bytes // len is the max.
+	head   int      // least recently used.
+	n      int
+	evict  bool // evict least recently used when full, rather than dropping put.
+	zeroed int  // least recently used known zeroed, where takeUnzeroed resumes.
+
+	low, high int // n range since last trim.
+}
+
+func newFreeList(max int, evict bool) *freeList {
+	return &freeList{ring: make([]*Bytes, max), evict: evict}
+}
+
+// nil when empty.
+func (l *freeList) get() *Bytes {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.n == 0 {
+		return nil
+	}
+	l.n--
+	l.low = min(l.low, l.n)
+	l.zeroed = min(l.zeroed, l.n)
+	i := (l.head + l.n) % len(l.ring)
+	b := l.ring[i]
+	l.ring[i] = nil
+	return b
+}
+
+// Returns the Bytes not kept, either b or an evicted one. Nil when all kept.
+func (l *freeList) put(b *Bytes) (dropped *Bytes) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.n == len(l.ring) {
+		if !l.evict {
+			return b
+		}
+		dropped = l.ring[l.head]
+		l.ring[l.head] = nil
+		l.head = (l.head + 1) % len(l.ring)
+		l.n--
+		l.zeroed = max(0, l.zeroed-1)
+	}
+	l.ring[(l.head+l.n)%len(l.ring)] = b
+	l.n++
+	l.high = max(l.high, l.n)
+	return dropped
+}
+
+// Removes the least recently used, nil when empty.
+func (l *freeList) evictOldest() *Bytes {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.n == 0 {
+		return nil
+	}
+	b := l.ring[l.head]
+	l.ring[l.head] = nil
+	l.head = (l.head + 1) % len(l.ring)
+	l.n--
+	l.low = min(l.low, l.n)
+	l.zeroed = max(0, l.zeroed-1)
+	return b
+}
+
+// Removes the least recently used Bytes not yet zeroed, for zeroing outside the lock and
+// returning with putOldest. Nil when none remain. Least recently used are most likely idle.
+func (l *freeList) takeUnzeroed() *Bytes {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for ; l.zeroed < l.n; l.zeroed++ {
+		i := (l.head + l.zeroed) % len(l.ring)
+		b := l.ring[i]
+		if b.zeroed {
+			continue
+		}
+		l.ring[i] = l.ring[l.head] // the oldest fills the hole, keeping those before it zeroed.
+		l.ring[l.head] = nil
+		l.head = (l.head + 1) % len(l.ring)
+		l.n--
+		return b
+	}
+	return nil
+}
+
+// Returns b from takeUnzeroed as the least recently used. Returns b back when full.
+func (l *freeList) putOldest(b *Bytes) (dropped *Bytes) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.n == len(l.ring) {
+		return b
+	}
+	l.head = (l.head + len(l.ring) - 1) % len(l.ring)
+	l.ring[l.head] = b
+	l.n++
+	if b.zeroed {
+		l.zeroed++
+	}
+	return nil
+}
+
+// Calls fn with each retained Bytes, which stay retained.
+func (l *freeList) each(fn func(b *Bytes)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i := range l.n {
+		fn(l.ring[(l.head+i)%len(l.ring)])
+	}
+}
+
+// Removes the least recently used Bytes that went unused since the last trim (the low watermark),
+// keeping at least floor. Resets the watermarks.
+func (l *freeList) trim(floor int) []*Bytes {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var trimmed []*Bytes
+	for range min(l.low, l.n-floor) {
+		trimmed = append(trimmed, l.ring[l.head])
+		l.ring[l.head] = nil
+		l.head = (l.head + 1) % len(l.ring)
+		l.n--
+	}
+	l.zeroed = max(0, l.zeroed-len(trimmed))
+	l.low = l.n
+	l.high = l.n
+	return trimmed
+}
+
+// Changes the max, removing the least recently used over it. Keeps evicting or dropping as before.
+func (l *freeList) resize(max int) []*Bytes {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var removed []*Bytes
+	for l.n > max {
+		removed = append(removed, l.ring[l.head])
+		l.ring[l.head] = nil
+		l.head = (l.head + 1) % len(l.ring)
+		l.n--
+	}
+	ring := make([]*Bytes, max)
+	for i := range l.n {
+		ring[i] = l.ring[(l.head+i)%len(l.ring)]
+	}
+	l.ring, l.head = ring, 0
+	l.low, l.high = min(l.low, l.n), min(l.high, l.n)
+	l.zeroed -= min(l.zeroed, len(removed))
+	return removed
+}
+
+func (l *freeList) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.n
+}
+
+func (l *freeList) watermarks() (low, high int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.low, l.high
+}
+
+// Removes all.
+func (l *freeList) drain() []*Bytes {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	drained := make([]*Bytes, 0, l.n)
+	for i := range l.n {
+		idx := (l.head + i) % len(l.ring)
+		drained = append(drained, l.ring[idx])
+		l.ring[idx] = nil
+	}
+	l.head, l.n, l.low, l.high, l.zeroed = 0, 0, 0, 0, 0
+	return drained
+}
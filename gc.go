@@ -0,0 +1,105 @@
+package bytepool
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// Approximate, as finalizers of lost Bytes can run a cycle after the GC that found them.
+type GCCycleStats struct {
+	Cycle    uint64 // starting at 1.
+	Survived uint64 // Bytes retained by the pool after the cycle.
+	Lost     uint64 // Bytes collected since the previous cycle, the sum of the below.
+
+	Cleared    uint64 // lost from sync.Pool buckets, which GC clears.
+	Dropped    uint64 // lost after the pool discarded them, such as over MaxRetained or by Drain.
+	Unreleased uint64 // lost while outstanding, never released.
+}
+
+// How a collected Bytes was lost.
+type lostKind uint8
+
+const (
+	lostUnreleased lostKind = iota
+	lostDropped
+	lostCleared
+)
+
+// Bytes collected by kind.
+type lostCounts [3]atomic.Uint64
+
+// Nil does nothing.
+func (c *lostCounts) add(k lostKind) {
+	if c != nil {
+		c[k].Add(1)
+	}
+}
+
+// calls fn from a new goroutine after each GC cycle until stop is closed.
+// Cycles are skipped if fn is still running.
+func onGC(stop <-chan struct{}, fn func()) {
+	cycles := make(chan struct{}, 1)
+
+	type sentinel struct{ _ *byte } // pointer avoids the tiny allocator, whose finalizers may not run.
+	var arm func()
+	arm = func() {
+		runtime.SetFinalizer(new(sentinel), func(*sentinel) {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			select {
+			case cycles <- struct{}{}:
+			default:
+			}
+			arm()
+		})
+	}
+	arm()
+
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case <-cycles:
+				fn()
+			}
+		}
+	}()
+}
+
+func (p *BucketPool) reportGC(report func(GCCycleStats)) {
+	var cycle uint64
+	var last [3]uint64
+	onGC(p.stop, func() {
+		var cur [3]uint64
+		var collected uint64
+		for k := range p.lost {
+			cur[k] = p.lost[k].Load()
+			collected += cur[k]
+		}
+		var puts, hits uint64
+		for _, sp := range p.pools {
+			puts += sp.puts.Load()
+			hits += sp.hits.Load()
+		}
+		var survived uint64
+		if held := puts - hits; puts > hits && held > collected {
+			survived = held - collected
+		}
+
+		cycle++
+		s := GCCycleStats{
+			Cycle:      cycle,
+			Survived:   survived,
+			Cleared:    cur[lostCleared] - last[lostCleared],
+			Dropped:    cur[lostDropped] - last[lostDropped],
+			Unreleased: cur[lostUnreleased] - last[lostUnreleased],
+		}
+		s.Lost = s.Cleared + s.Dropped + s.Unreleased
+		report(s)
+		last = cur
+	})
+}
@@ -0,0 +1,95 @@
+package bytepool_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_gcReport(t *testing.T) {
+	t.Parallel()
+
+	reports := make(chan bytepool.GCCycleStats, 100)
+	pool := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{
+		GCReport: func(s bytepool.GCCycleStats) {
+			select {
+			case reports <- s:
+			default:
+			}
+		},
+	})
+	defer pool.Close()
+
+	var held []*bytepool.Bytes
+	for range 10 {
+		held = append(held, pool.GetGrown(8))
+	}
+	for _, b := range held {
+		b.Release()
+	}
+
+	var lost, cleared uint64
+	var last bytepool.GCCycleStats
+	for range 20 { // sync.Pool drops after two cycles, finalizers run a cycle later.
+		runtime.GC()
+		select {
+		case last = <-reports:
+			lost += last.Lost
+			cleared += last.Cleared
+		case <-time.After(time.Second):
+		}
+		if lost == 10 {
+			break
+		}
+	}
+	diffFatal(t, uint64(10), lost)
+	diffFatal(t, uint64(10), cleared)
+	diffFatal(t, uint64(0), last.Survived)
+	if last.Cycle < 2 {
+		t.Fatal(last.Cycle)
+	}
+}
+
+func TestBucket_gcReportLostKinds(t *testing.T) {
+	t.Parallel()
+
+	reports := make(chan bytepool.GCCycleStats, 100)
+	pool := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{
+		MaxRetained: 2,
+		GCReport: func(s bytepool.GCCycleStats) {
+			select {
+			case reports <- s:
+			default:
+			}
+		},
+	})
+	defer pool.Close()
+
+	func() {
+		var held []*bytepool.Bytes
+		for range 5 {
+			held = append(held, pool.GetGrown(8))
+		}
+		for _, b := range held[:3] { // one dropped over MaxRetained.
+			b.Release()
+		}
+	}() // two never released.
+
+	var sum bytepool.GCCycleStats
+	for range 20 {
+		runtime.GC()
+		select {
+		case s := <-reports:
+			sum.Lost += s.Lost
+			sum.Dropped += s.Dropped
+			sum.Unreleased += s.Unreleased
+		case <-time.After(time.Second):
+		}
+		if sum.Lost == 3 {
+			break
+		}
+	}
+	diffFatal(t, bytepool.GCCycleStats{Lost: 3, Dropped: 1, Unreleased: 2}, sum)
+}
@@ -0,0 +1,25 @@
+package bytepool
+
+import (
+	"hash"
+	"io"
+)
+
+const copyBufSize = 32 * 1024 // same as io.Copy
+
+// Digest of r in Bytes from p, reading through a pooled buffer.
+// Call Release on the returned Bytes to return it to the pool.
+func SumInto(p SizedPooler, newHash func() hash.Hash, r io.Reader) (*Bytes, error) {
+	h := newHash()
+
+	buf := p.GetFilled(copyBufSize)
+	defer buf.Release()
+
+	if _, err := io.CopyBuffer(h, r, buf.B); err != nil {
+		return nil, err
+	}
+
+	out := p.GetGrown(h.Size())
+	out.B = h.Sum(out.B)
+	return out, nil
+}
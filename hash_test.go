@@ -0,0 +1,35 @@
+package bytepool_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestSumInto(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(16, 64*1024)
+
+	data := bytes.Repeat([]byte("abc"), 50_000)
+	want := sha256.Sum256(data)
+
+	// HalfReader hides WriterTo so the pooled buffer is used.
+	got, err := bytepool.SumInto(pool, sha256.New, iotest.HalfReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, want[:], got.B)
+	got.Release()
+
+	wantErr := errors.New("read")
+	_, err = bytepool.SumInto(pool, sha256.New, io.MultiReader(bytes.NewReader(data), iotest.ErrReader(wantErr)))
+	if !errors.Is(err, wantErr) {
+		t.Fatal(err)
+	}
+}
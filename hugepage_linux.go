@@ -0,0 +1,80 @@
+//go:build linux && !arm
+
+package bytepool
+
+import (
+	"sync"
+	"syscall"
+)
+
+const hugePageSize = 2 << 20
+
+// Allocates from hugepage backed anonymous mappings to reduce TLB misses for large buffers.
+// Explicit hugepages (MAP_HUGETLB) are tried first, then transparent hugepages (MADV_HUGEPAGE),
+// falling back to the Go heap if mapping fails.
+// Only worthwhile for large sizes, as each allocation is rounded up to at least a page.
+type HugePageAllocator struct {
+	mu      sync.Mutex
+	regions map[uintptr][]byte // keyed by region start.
+}
+
+func NewHugePageAllocator() *HugePageAllocator {
+	return &HugePageAllocator{regions: make(map[uintptr][]byte)}
+}
+
+// Returned slice has len=0 and cap=c.
+func (a *HugePageAllocator) Alloc(c int) []byte {
+	if c <= 0 {
+		return nil
+	}
+
+	const prot = syscall.PROT_READ | syscall.PROT_WRITE
+	const flags = syscall.MAP_ANON | syscall.MAP_PRIVATE
+
+	hugeLen := (c + hugePageSize - 1) &^ (hugePageSize - 1)
+	region, err := syscall.Mmap(-1, 0, hugeLen, prot, flags|syscall.MAP_HUGETLB)
+	if err != nil {
+		region, err = syscall.Mmap(-1, 0, hugeLen, prot, flags)
+		if err != nil {
+			return make([]byte, 0, c)
+		}
+		// best effort, region is still usable without.
+		_ = syscall.Madvise(region, syscall.MADV_HUGEPAGE)
+	}
+
+	a.mu.Lock()
+	a.regions[regionKey(region)] = region
+	a.mu.Unlock()
+
+	return region[:0:c]
+}
+
+// Unmaps b if it was returned by Alloc, otherwise does nothing.
+// b must not be used after.
+func (a *HugePageAllocator) Free(b []byte) {
+	if cap(b) == 0 {
+		return
+	}
+	k := regionKey(b)
+
+	a.mu.Lock()
+	region, ok := a.regions[k]
+	delete(a.regions, k)
+	a.mu.Unlock()
+
+	if ok {
+		_ = syscall.Munmap(region)
+	}
+}
+
+// Returns the pages of b to the OS if it was returned by Alloc. b remains usable, reading as zero.
+func (a *HugePageAllocator) ReleaseOSMemory(b []byte) bool {
+	if cap(b) == 0 {
+		return false
+	}
+	a.mu.Lock()
+	region, ok := a.regions[regionKey(b)]
+	a.mu.Unlock()
+
+	return ok && syscall.Madvise(region, syscall.MADV_DONTNEED) == nil
+}
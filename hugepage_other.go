@@ -0,0 +1,26 @@
+//go:build !linux || arm
+
+package bytepool
+
+// Allocates from the Go heap, hugepages are only supported on Linux.
+type HugePageAllocator struct{}
+
+func NewHugePageAllocator() *HugePageAllocator {
+	return &HugePageAllocator{}
+}
+
+// Returned slice has len=0 and cap=c.
+func (a *HugePageAllocator) Alloc(c int) []byte {
+	if c <= 0 {
+		return nil
+	}
+	return make([]byte, 0, c)
+}
+
+// Does nothing, the Go heap reclaims b.
+func (a *HugePageAllocator) Free(b []byte) {}
+
+// Does nothing, see debug.FreeOSMemory for the Go heap.
+func (a *HugePageAllocator) ReleaseOSMemory(b []byte) bool {
+	return false
+}
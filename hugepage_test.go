@@ -0,0 +1,68 @@
+package bytepool_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestHugePageAllocator(t *testing.T) {
+	t.Parallel()
+
+	a := bytepool.NewHugePageAllocator()
+
+	for _, c := range []int{1, 4096, 3 << 20} {
+		b := a.Alloc(c)
+		diffFatal(t, 0, len(b))
+		diffFatal(t, c, cap(b))
+
+		b = b[:c]
+		for i := range b {
+			b[i] = byte(i)
+		}
+		if b[c-1] != byte(c-1) {
+			t.Fatal(b[c-1])
+		}
+		a.Free(b)
+	}
+
+	diffFatal(t, 0, cap(a.Alloc(0)))
+
+	a.Free(make([]byte, 10)) // not from Alloc, no-op.
+	a.Free(nil)
+}
+
+func TestBucket_ReleaseOSMemory(t *testing.T) {
+	t.Parallel()
+
+	for _, o := range []bytepool.BucketPoolOptions{
+		{Allocator: bytepool.NewHugePageAllocator(), MaxRetained: 4},
+		{Allocator: bytepool.NewHugePageAllocator()},
+	} {
+		pool := bytepool.NewBucketOptions([]int{1 << 20}, o)
+
+		b := pool.GetFilled(1 << 20)
+		for i := range b.B {
+			b.B[i] = 1
+		}
+		b.Release()
+
+		released := pool.ReleaseOSMemory()
+		if runtime.GOOS != "linux" || runtime.GOARCH == "arm" {
+			diffFatal(t, 0, released)
+			continue
+		}
+		if o.MaxRetained > 0 {
+			diffFatal(t, 1<<20, released)
+		} // else sync.Pool might have dropped.
+
+		if released > 0 {
+			b = pool.GetFilled(1 << 20) // not cleared, released pages read as zero.
+			diffFatal(t, make([]byte, 1<<20), b.B)
+			b.Release()
+		}
+	}
+
+	diffFatal(t, 0, bytepool.NewBucket(8, 64).ReleaseOSMemory()) // Go heap
+}
@@ -0,0 +1,61 @@
+package bytepool
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+const internShards = 16
+
+// Deduplicates hot strings, such as converting repeated header names, in a bounded sharded table.
+type Interner struct {
+	seed     maphash.Seed
+	maxShard int
+	shards   [internShards]internShard
+}
+
+type internShard struct {
+	mu sync.RWMutex
+	m  map[string]string
+}
+
+// Retains up to about maxStrings, which must be >= 1. A full shard is cleared
+// so the table follows the currently hot strings.
+func NewInterner(maxStrings int) *Interner {
+	if maxStrings < 1 {
+		panic("maxStrings < 1")
+	}
+	in := &Interner{
+		seed:     maphash.MakeSeed(),
+		maxShard: max(1, maxStrings/internShards),
+	}
+	for i := range in.shards {
+		in.shards[i].m = make(map[string]string)
+	}
+	return in
+}
+
+// String of b, without allocating when already interned.
+func (in *Interner) Intern(b []byte) string {
+	sh := &in.shards[maphash.Bytes(in.seed, b)%internShards]
+
+	sh.mu.RLock()
+	s, ok := sh.m[string(b)] // no allocation for lookup
+	sh.mu.RUnlock()
+	if ok {
+		return s
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	if s, ok := sh.m[string(b)]; ok {
+		return s
+	}
+	if len(sh.m) >= in.maxShard {
+		clear(sh.m)
+	}
+	s = string(b)
+	sh.m[s] = s
+	return s
+}
@@ -0,0 +1,50 @@
+package bytepool_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"unsafe"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestInterner(t *testing.T) {
+	t.Parallel()
+
+	in := bytepool.NewInterner(100)
+
+	s1 := in.Intern([]byte("content-type"))
+	s2 := in.Intern([]byte("content-type"))
+	diffFatal(t, "content-type", s1)
+	if unsafe.StringData(s1) != unsafe.StringData(s2) {
+		t.Fatal("not interned")
+	}
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 1000 {
+				want := fmt.Sprint(i, "-", j%300)
+				if got := in.Intern([]byte(want)); got != want {
+					t.Error(want, got)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestInternerAllocs(t *testing.T) {
+	in := bytepool.NewInterner(100)
+
+	b := []byte("accept")
+	in.Intern(b)
+	allocs := testing.AllocsPerRun(100, func() {
+		in.Intern(b)
+	})
+	diffFatal(t, 0.0, allocs)
+}
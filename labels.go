@@ -0,0 +1,30 @@
+package bytepool
+
+import (
+	"context"
+	"runtime/pprof"
+	"strconv"
+)
+
+// nil when profile labels are off.
+func allocLabels(on bool, name, size string) *pprof.LabelSet {
+	if !on {
+		return nil
+	}
+	l := pprof.Labels("bytepool", name, "bytepool_size", size)
+	return &l
+}
+
+func sizeLabel(size int) string {
+	return strconv.Itoa(size)
+}
+
+// Labels the allocation. As pprof.Do, the goroutine is left with the labels of
+// context.Background, none, replacing any of the caller's own.
+func labeledAlloc(labels pprof.LabelSet, alloc func() *Bytes) *Bytes {
+	var b *Bytes
+	pprof.Do(context.Background(), labels, func(context.Context) {
+		b = alloc()
+	})
+	return b
+}
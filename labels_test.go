@@ -0,0 +1,62 @@
+package bytepool_test
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_profileLabels(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{4, 8}, bytepool.BucketPoolOptions{
+		Name:          "test",
+		ProfileLabels: true,
+	})
+
+	b := pool.GetGrown(5)
+	diffFatal(t, 8, cap(b.B))
+	b.Release()
+
+	b = pool.GetFilled(9) // over
+	diffFatal(t, 9, len(b.B))
+	diffFatal(t, 9, cap(b.B))
+	b.Release()
+
+	s := pool.Stats()
+	diffFatal(t, uint64(1), s.Misses)
+	diffFatal(t, uint64(2), s.Overs)
+}
+
+func TestBucket_profileLabelsReplaceCallers(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{4, 8}, bytepool.BucketPoolOptions{
+		Name:          "test",
+		ProfileLabels: true,
+	})
+
+	// labels show in the goroutine profile.
+	labeled := func() bool {
+		var buf bytes.Buffer
+		if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+			t.Fatal(err)
+		}
+		return strings.Contains(buf.String(), `"caller":"`+t.Name()+`"`)
+	}
+
+	pprof.Do(context.Background(), pprof.Labels("caller", t.Name()), func(ctx context.Context) {
+		diffFatal(t, true, labeled())
+
+		pool.GetGrown(5).Release() // miss
+		diffFatal(t, false, labeled())
+
+		pprof.SetGoroutineLabels(ctx)
+		diffFatal(t, true, labeled())
+	})
+}
@@ -0,0 +1,59 @@
+package bytepool
+
+import (
+	"math/bits"
+	"time"
+)
+
+// Allocation times of a bucket's misses, each an upper bound within 2x.
+type AllocLatencyStats struct {
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+// Counts by bits.Len of nanoseconds, so bin i holds durations under 1<<i ns.
+type latencyHisto struct {
+	bins [64]counter
+}
+
+func (h *latencyHisto) observe(d time.Duration) {
+	h.bins[bits.Len64(uint64(max(0, d)))].Add(1)
+}
+
+func (h *latencyHisto) stats() AllocLatencyStats {
+	var counts [64]uint64
+	var total uint64
+	for i := range h.bins {
+		counts[i] = h.bins[i].Load()
+		total += counts[i]
+	}
+	if total == 0 {
+		return AllocLatencyStats{}
+	}
+	percentile := func(p float64) time.Duration {
+		want := uint64(float64(total)*p + 0.5)
+		var n uint64
+		for i, c := range counts {
+			if n += c; n >= max(want, 1) {
+				return time.Duration(uint64(1) << i)
+			}
+		}
+		return 0
+	}
+	s := AllocLatencyStats{P50: percentile(.5), P90: percentile(.9), P99: percentile(.99)}
+	for i := len(counts) - 1; i >= 0; i-- {
+		if counts[i] > 0 {
+			s.Max = time.Duration(uint64(1) << i)
+			break
+		}
+	}
+	return s
+}
+
+func (h *latencyHisto) reset() {
+	for i := range h.bins {
+		h.bins[i].Store(0)
+	}
+}
@@ -0,0 +1,32 @@
+package bytepool_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_allocLatency(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8, 1 << 20}, bytepool.BucketPoolOptions{AllocLatency: true, MaxRetained: 10}) // listed after ResetStats by Pooled.
+
+	var held []*bytepool.Bytes
+	for range 10 {
+		held = append(held, pool.GetGrown(1<<20))
+	}
+	for _, b := range held {
+		b.Release()
+	}
+
+	s := pool.Stats()
+	diffFatal(t, 1, len(s.Buckets))
+	l := s.Buckets[0].AllocLatency
+	if l.P50 <= 0 || l.P90 < l.P50 || l.P99 < l.P90 || l.Max < l.P99 {
+		t.Fatal(l)
+	}
+
+	pool.ResetStats()
+	diffFatal(t, bytepool.AllocLatencyStats{}, pool.Stats().Buckets[0].AllocLatency)
+}
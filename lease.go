@@ -0,0 +1,92 @@
+package bytepool
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+type LeaseExpiry struct {
+	Size     int // cap when leased.
+	Duration time.Duration
+}
+
+const (
+	leaseHeld = iota
+	leaseReleased
+	leaseExpired
+)
+
+// Origin of a leased Bytes, passing its release on to the pool.
+type lease struct {
+	pool  *BucketPool
+	sp    *sizedPool // nil for overs.
+	size  int
+	d     time.Duration
+	timer *time.Timer
+	state atomic.Int32
+}
+
+// As GetGrown, expecting a Release within d. Memory can't be taken back from a holder, so on
+// expiry the Bytes is written off: no longer counted in Outstanding or Memory, counted in
+// LeasesExpired and reported to OnLeaseExpired. A late Release still returns it to the pool,
+// counted in LateReleases. Bytes stay leased if Put or Adopted into other pools.
+func (p *BucketPool) GetLeased(c int, d time.Duration) *Bytes {
+	b := p.GetGrown(c)
+	if _, ok := b.pool.(discarder); ok {
+		return b // not pooled, nothing to lose.
+	}
+	l := &lease{pool: p, size: cap(b.B), d: d}
+	if _, sp := p.findPool(l.size); sp != nil {
+		l.sp = sp
+	}
+	b.pool = l
+	l.timer = time.AfterFunc(d, l.expire)
+	return b
+}
+
+func (l *lease) expire() {
+	if !l.state.CompareAndSwap(leaseHeld, leaseExpired) {
+		return
+	}
+	l.writeOff(-1)
+
+	entry := l.pool.overGate.enter()
+	l.pool.expired.Add(1)
+	l.pool.overGate.exit(entry)
+
+	l.pool.warn.warn(warnLeaseExpired, "bytepool lease expired before release",
+		slog.Int("size", l.size),
+		slog.Duration("duration", l.d),
+	)
+	if l.pool.onExpired != nil {
+		l.pool.onExpired(LeaseExpiry{Size: l.size, Duration: l.d})
+	}
+}
+
+// Removes (sign -1) or restores (1) the Bytes in the pool's gauges.
+func (l *lease) writeOff(sign int) {
+	if l.sp == nil {
+		l.pool.mem.add(sign * l.size)
+		return
+	}
+	l.sp.mem.add(sign * l.size)
+	entry := l.sp.gate.enter()
+	l.sp.out.Add(int64(sign))
+	l.sp.gate.exit(entry)
+}
+
+func (l *lease) put(b *Bytes) {
+	if l.state.CompareAndSwap(leaseHeld, leaseReleased) {
+		l.timer.Stop()
+	} else if l.state.CompareAndSwap(leaseExpired, leaseReleased) {
+		l.writeOff(1) // as the put removes it again.
+		entry := l.pool.overGate.enter()
+		l.pool.lateRelease.Add(1)
+		l.pool.overGate.exit(entry)
+	} else {
+		return // released twice.
+	}
+	b.pool = l.pool
+	l.pool.put(b)
+}
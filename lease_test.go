@@ -0,0 +1,34 @@
+package bytepool_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_GetLeased(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	expiries := make(chan bytepool.LeaseExpiry, 1)
+	pool := bytepool.NewBucketOptions([]int{100}, bytepool.BucketPoolOptions{
+		MaxRetained:    4,
+		MemoryLimit:    bytepool.MemoryLimit{Hard: 1000},
+		OnLeaseExpired: func(e bytepool.LeaseExpiry) { expiries <- e },
+	})
+
+	b := pool.GetLeased(10, time.Hour)
+	bytepool.NewSync().Put(b) // stays leased.
+	s := pool.Stats()
+	diffFatal(t, [3]int64{0, 0, 1}, [3]int64{s.Outstanding, s.Memory - 100, int64(s.Pooled)})
+
+	b = pool.GetLeased(10, time.Millisecond)
+	diffFatal(t, bytepool.LeaseExpiry{Size: 100, Duration: time.Millisecond}, <-expiries)
+	s = pool.Stats()
+	diffFatal(t, [3]int64{0, 0, 1}, [3]int64{s.Outstanding, s.Memory, int64(s.LeasesExpired)})
+
+	b.Release() // late.
+	s = pool.Stats()
+	diffFatal(t, [4]int64{0, 100, 1, 1}, [4]int64{s.Outstanding, s.Memory, int64(s.LateReleases), int64(s.Pooled)})
+}
@@ -0,0 +1,83 @@
+package bytepool
+
+import (
+	"bytes"
+	"io"
+)
+
+// Calls fn with each line of r, without the line ending (\n or \r\n), including a final line
+// without one. fn owns each line and must Release it. Returns the first error from r or fn.
+//
+// Lines start from p.Get when p is a Pooler. With a *BucketPool lines start from a BucketPooler,
+// so their capacity follows recent line lengths.
+func ReadLines(r io.Reader, p SizedPooler, fn func(line *Bytes) error) error {
+	var pooler *BucketPooler
+	get := func() *Bytes { return p.GetGrown(0) }
+	switch pp := p.(type) {
+	case *BucketPool:
+		pooler = pp.Pooler(BucketPoolerOptions{ChooseInc: 100})
+		get = pooler.Get
+	case Pooler:
+		get = pp.Get
+	}
+
+	grow := func(line *Bytes, c int) *Bytes {
+		if c <= cap(line.B) {
+			return line
+		}
+		nb := p.GetGrown(c)
+		nb.B = append(nb.B, line.B...)
+		if pooler == nil {
+			line.Release()
+		} else { // partial lines would skew the histogram.
+			pooler.pool.Put(line)
+			pooler.Adopt(nb)
+		}
+		return nb
+	}
+
+	emit := func(line *Bytes) error {
+		line.B = bytes.TrimSuffix(line.B, []byte{'\r'})
+		return fn(line)
+	}
+
+	buf := p.GetFilled(4096)
+	defer buf.Release()
+	buf.B = buf.B[:cap(buf.B)]
+
+	var line *Bytes
+	for {
+		n, err := r.Read(buf.B)
+		data := buf.B[:n]
+		for len(data) > 0 {
+			if line == nil {
+				line = get()
+			}
+			i := bytes.IndexByte(data, '\n')
+			if i < 0 {
+				line = grow(line, len(line.B)+len(data))
+				line.B = append(line.B, data...)
+				break
+			}
+			line = grow(line, len(line.B)+i)
+			line.B = append(line.B, data[:i]...)
+			data = data[i+1:]
+
+			l := line
+			line = nil
+			if err := emit(l); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			if line != nil {
+				return emit(line)
+			}
+			return nil
+		}
+		if err != nil {
+			line.Release()
+			return err
+		}
+	}
+}
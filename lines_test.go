@@ -0,0 +1,75 @@
+package bytepool_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestReadLines(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("y", 10000)
+	input := "a\r\nbb\n\n" + long + "\r\nlast\r"
+
+	run := func(t *testing.T, p bytepool.SizedPooler) {
+		var got []string
+		err := bytepool.ReadLines(iotest.HalfReader(strings.NewReader(input)), p, func(line *bytepool.Bytes) error {
+			got = append(got, string(line.B))
+			line.Release()
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		diffFatal(t, []string{"a", "bb", "", long, "last"}, got)
+	}
+	t.Run("sync", func(t *testing.T) {
+		run(t, bytepool.NewSync())
+	})
+	t.Run("bucket", func(t *testing.T) {
+		pool := bytepool.NewBucket(8, 1<<16)
+		run(t, pool)
+		st := pool.Stats()
+		diffFatal(t, st.Gets, st.Puts)
+	})
+}
+
+func TestReadLines_empty(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"", "\n"} {
+		var n int
+		err := bytepool.ReadLines(strings.NewReader(input), bytepool.NewSync(), func(line *bytepool.Bytes) error {
+			n++
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		diffFatal(t, len(input), n)
+	}
+}
+
+func TestReadLines_errors(t *testing.T) {
+	t.Parallel()
+
+	errFn := errors.New("fn")
+	err := bytepool.ReadLines(strings.NewReader("a\nb\n"), bytepool.NewSync(), func(line *bytepool.Bytes) error {
+		return errFn
+	})
+	if !errors.Is(err, errFn) {
+		t.Fatal(err)
+	}
+
+	errRead := errors.New("read")
+	err = bytepool.ReadLines(iotest.ErrReader(errRead), bytepool.NewSync(), func(line *bytepool.Bytes) error {
+		return nil
+	})
+	if !errors.Is(err, errRead) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,197 @@
+package bytepool
+
+import (
+	"sync"
+)
+
+type LocalOptions struct {
+	MaxPerBucket int // Bytes kept locally per bucket, over which a batch spills to global. Defaults to 16.
+	Batch        int // Bytes moved per refill or spill. Defaults to half of MaxPerBucket.
+}
+
+// Local cache for one component over a shared global BucketPool. Refills from and spills to
+// the global pool in batches, cutting contention while memory stays bounded in one place.
+type LocalPool struct {
+	global *BucketPool
+	max    int
+	batch  int
+
+	mu      sync.Mutex
+	lists   [][]*Bytes // by bucket, LIFO.
+	closed  bool       // Gets and puts go to global.
+	hits    uint64
+	refills uint64
+	spills  uint64
+}
+
+func (p *BucketPool) Local(o LocalOptions) *LocalPool {
+	if o.MaxPerBucket <= 0 {
+		o.MaxPerBucket = 16
+	}
+	if o.Batch <= 0 {
+		o.Batch = max(1, o.MaxPerBucket/2)
+	}
+	o.Batch = min(o.Batch, o.MaxPerBucket)
+
+	return &LocalPool{
+		global: p,
+		max:    o.MaxPerBucket,
+		batch:  o.Batch,
+		lists:  make([][]*Bytes, len(p.pools)),
+	}
+}
+
+func (l *LocalPool) GetGrown(c int) *Bytes {
+	idx, sp := l.global.findPool(c)
+	if sp == nil {
+		b := l.global.GetGrown(c)
+		b.pool = l
+		return b
+	}
+
+	b, closed := l.take(idx, sp)
+	if closed {
+		b = l.global.GetGrown(c)
+	} else if b == nil {
+		return sp.allocate(l) // unlocked, as AllocLimit can wait.
+	}
+	b.pool = l
+	return b
+}
+
+// Cached Bytes of bucket idx, refilling when empty. Nil when none, or when closed.
+func (l *LocalPool) take(idx int, sp *sizedPool) (_ *Bytes, closed bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil, true
+	}
+	if len(l.lists[idx]) == 0 {
+		l.refill(idx, sp)
+	} else {
+		l.hits++
+	}
+	list := l.lists[idx]
+	if len(list) == 0 {
+		return nil, false
+	}
+	b := list[len(list)-1]
+	list[len(list)-1] = nil
+	l.lists[idx] = list[:len(list)-1]
+	b.B = Sized(b.B, sp.size)
+	return b, false
+}
+
+func (l *LocalPool) GetFilled(length int) *Bytes {
+	b := l.GetGrown(length)
+	b.B = b.B[:length]
+	return b
+}
+
+func (l *LocalPool) Put(b *Bytes) {
+	if b != nil {
+		adopt(b, l)
+		b.Release()
+	}
+}
+
+func (l *LocalPool) Adopt(b *Bytes) {
+	if b != nil {
+		adopt(b, l)
+	}
+}
+
+// Spills every local Bytes to the global pool, such as when the component is done.
+// The LocalPool remains usable.
+func (l *LocalPool) Flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for idx, list := range l.lists {
+		l.spill(idx, len(list))
+	}
+}
+
+// Flushes, with later Gets and puts going to the global pool.
+func (l *LocalPool) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+	for idx, list := range l.lists {
+		l.spill(idx, len(list))
+	}
+}
+
+type LocalPoolStats struct {
+	Retained int // local Bytes.
+	Hits     uint64
+	Refills  uint64
+	Spills   uint64
+}
+
+func (l *LocalPool) Stats() LocalPoolStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s := LocalPoolStats{
+		Hits:    l.hits,
+		Refills: l.refills,
+		Spills:  l.spills,
+	}
+	for _, list := range l.lists {
+		s.Retained += len(list)
+	}
+	return s
+}
+
+func (l *LocalPool) put(b *Bytes) {
+	if b == nil {
+		return
+	}
+	idx, sp := l.global.findPool(cap(b.B))
+	if sp == nil {
+		l.global.put(b)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		sp.put(b)
+		return
+	}
+	b.B = b.B[:0]
+	l.lists[idx] = append(l.lists[idx], b)
+	if len(l.lists[idx]) > l.max {
+		l.spill(idx, l.batch)
+	}
+}
+
+// must hold mu.
+func (l *LocalPool) refill(idx int, sp *sizedPool) {
+	l.refills++
+	for range l.batch {
+		b := sp.getNoAlloc(l)
+		if b == nil {
+			return
+		}
+		l.lists[idx] = append(l.lists[idx], b)
+	}
+}
+
+// spills the n oldest. Must hold mu.
+func (l *LocalPool) spill(idx, n int) {
+	if n <= 0 {
+		return
+	}
+	l.spills++
+	list := l.lists[idx]
+	for _, b := range list[:n] {
+		l.global.pools[idx].put(b)
+	}
+	l.lists[idx] = append(list[:0], list[n:]...)
+	clear(list[len(list)-n:])
+}
@@ -0,0 +1,86 @@
+package bytepool_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestLocalPool(t *testing.T) {
+	t.Parallel()
+
+	global := bytepool.NewBucketOptions([]int{8, 16}, bytepool.BucketPoolOptions{MaxRetained: 100})
+	local := global.Local(bytepool.LocalOptions{MaxPerBucket: 4, Batch: 2})
+
+	var held []*bytepool.Bytes
+	for range 5 {
+		held = append(held, local.GetGrown(16))
+	}
+	for _, b := range held {
+		b.Release()
+	}
+	diffFatal(t, bytepool.LocalPoolStats{Retained: 3, Refills: 5, Spills: 1}, local.Stats())
+
+	for range 4 {
+		b := local.GetFilled(10)
+		diffFatal(t, 10, len(b.B))
+		diffFatal(t, 16, cap(b.B))
+	}
+	// 3 hits, then refill of the 2 spilled
+	diffFatal(t, bytepool.LocalPoolStats{Retained: 1, Hits: 3, Refills: 6, Spills: 1}, local.Stats())
+
+	local.Flush()
+	diffFatal(t, bytepool.LocalPoolStats{Hits: 3, Refills: 6, Spills: 2}, local.Stats())
+
+	b := local.GetGrown(17) // over
+	diffFatal(t, 17, cap(b.B))
+	b.Release()
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				b := local.GetGrown(i % 17)
+				b.Release()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLocalPool_allocWaitUnlocked(t *testing.T) {
+	t.Parallel()
+
+	global := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{
+		MaxRetained: 10,
+		AllocLimit:  bytepool.AllocLimit{PerSecond: 1, Policy: bytepool.AllocWait, MaxWait: time.Second},
+	})
+	local := global.Local(bytepool.LocalOptions{MaxPerBucket: 4, Batch: 2})
+
+	held := local.GetGrown(8) // takes the only token
+
+	got := make(chan struct{})
+	go func() {
+		defer close(got)
+		local.GetGrown(8) // waits on the limiter
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	released := make(chan struct{})
+	go func() {
+		defer close(released)
+		held.Release()
+	}()
+	select {
+	case <-released:
+	case <-got:
+		t.Fatal("get did not wait")
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("release blocked behind a waiting get")
+	}
+	<-got
+}
@@ -0,0 +1,178 @@
+package bytepool
+
+import (
+	"errors"
+	"log/slog"
+	"sync/atomic"
+)
+
+// Returned by TryGetGrown when a Get would allocate over a MemoryLimit Hard limit with FailGets.
+var ErrMemoryLimit = errors.New("bytepool: over hard memory limit")
+
+// Budgets the bytes of a pool's Bytes, outstanding or retained, with a soft limit degrading
+// gradually and a hard limit. Requires MaxRetained, as sync.Pool drops can't be accounted.
+// Bytes from other pools count once released here, and stop counting in their origin's
+// budget when Put or Adopted. Bytes never released stay counted, see GetLeased.
+type MemoryLimit struct {
+	// Over, each put evicts the least recently used retained Bytes of its bucket, shrinking
+	// idle memory while hot Bytes still recycle. Zero is unlimited.
+	Soft int64
+
+	// Over, puts are dropped. Zero is unlimited.
+	Hard int64
+
+	// Over Hard, TryGetGrown returns ErrMemoryLimit rather than allocating. Other Gets allocate.
+	FailGets bool
+
+	// Warns once each time a limit is crossed, at most once per LogInterval.
+	Logger *slog.Logger
+
+	// Apportions Soft and Hard across buckets, in size order, so one bucket can't take the
+	// whole budget. Each must be positive and there must be one per bucket. Nil budgets the
+	// pool as a whole. Overs only count against the pool.
+	Weights []int
+}
+
+type memoryLimiter struct {
+	soft, hard atomic.Int64 // zero is unlimited.
+	failGets   bool
+	warn       *warner // nil without a Logger, and for buckets.
+
+	parent        *memoryLimiter // pool of a bucket's limiter with Weights.
+	weight, total int64          // of the parent's limits, with parent.
+
+	bytes      atomic.Int64
+	softTrims  counter
+	hardDrops  counter
+	softWarned atomic.Bool
+	hardWarned atomic.Bool
+}
+
+// nil when off.
+func newMemoryLimiter(o MemoryLimit, maxRetained int, w *warner) *memoryLimiter {
+	if maxRetained <= 0 || (o.Soft <= 0 && o.Hard <= 0) {
+		return nil
+	}
+	m := &memoryLimiter{
+		failGets: o.FailGets,
+		warn:     w,
+	}
+	m.setLimits(o.Soft, o.Hard)
+	return m
+}
+
+func (m *memoryLimiter) setLimits(soft, hard int64) {
+	m.soft.Store(max(0, soft))
+	m.hard.Store(max(0, hard))
+}
+
+// limiters by bucket, each the pool's with nil Weights.
+func (m *memoryLimiter) buckets(o MemoryLimit, sizes []int) []*memoryLimiter {
+	buckets := make([]*memoryLimiter, len(sizes))
+	if o.Weights == nil {
+		for i := range buckets {
+			buckets[i] = m
+		}
+		return buckets
+	}
+	var total int64 // Weights checked by checkBucket.
+	for _, w := range o.Weights {
+		total += int64(w)
+	}
+	for i, w := range o.Weights {
+		buckets[i] = &memoryLimiter{parent: m, weight: int64(w), total: total}
+		buckets[i].apportion()
+	}
+	return buckets
+}
+
+// Sets limits to the weighted share of the parent's.
+func (m *memoryLimiter) apportion() {
+	m.setLimits(m.parent.soft.Load()*m.weight/m.total, m.parent.hard.Load()*m.weight/m.total)
+}
+
+// n is negative when Bytes leave the pool. Nil does nothing.
+func (m *memoryLimiter) add(n int) {
+	if m == nil {
+		return
+	}
+	v := m.bytes.Add(int64(n))
+	m.crossed(v, m.soft.Load(), &m.softWarned, warnSoftLimit, "bytepool over soft memory limit")
+	m.crossed(v, m.hard.Load(), &m.hardWarned, warnHardLimit, "bytepool over hard memory limit")
+	m.parent.add(n)
+}
+
+// Bytes of a Bytes counted against a limiter, so only what was counted is removed.
+type memCount struct {
+	m *memoryLimiter
+	n int
+}
+
+// Counts n bytes of b against m, removing any count of b against another limiter. Nil m uncounts b.
+func countMem(b *Bytes, m *memoryLimiter, n int) {
+	if m == nil {
+		n = 0
+	}
+	if b.counted == (memCount{m, n}) {
+		return
+	}
+	b.counted.m.add(-b.counted.n)
+	m.add(n)
+	b.counted = memCount{m, n}
+}
+
+// where pool wide counters are.
+func (m *memoryLimiter) root() *memoryLimiter {
+	if m.parent != nil {
+		return m.parent
+	}
+	return m
+}
+
+func (m *memoryLimiter) crossed(v, limit int64, warned *atomic.Bool, kind int, msg string) {
+	if limit <= 0 {
+		return
+	}
+	if v <= limit {
+		warned.Store(false)
+		return
+	}
+	if m.warn != nil && warned.CompareAndSwap(false, true) {
+		m.warn.warn(kind, msg,
+			slog.Int64("bytes", v),
+			slog.Int64("limit", limit),
+		)
+	}
+}
+
+func (m *memoryLimiter) overSoft() bool {
+	if m == nil {
+		return false
+	}
+	soft := m.soft.Load()
+	return soft > 0 && m.bytes.Load() > soft || m.parent.overSoft()
+}
+
+func (m *memoryLimiter) overHard() bool {
+	if m == nil {
+		return false
+	}
+	hard := m.hard.Load()
+	return hard > 0 && m.bytes.Load() > hard || m.parent.overHard()
+}
+
+func (m *memoryLimiter) failGetsOverHard() bool {
+	return m != nil && m.failGets && m.overHard()
+}
+
+// Adds n unless that would go over a hard limit. Nil always adds.
+// Racing adds can still go over, as with gets.
+func (m *memoryLimiter) reserve(n int) bool {
+	for l := m; l != nil; l = l.parent {
+		if hard := l.hard.Load(); hard > 0 && l.bytes.Load()+int64(n) > hard {
+			return false
+		}
+	}
+	m.add(n)
+	return true
+}
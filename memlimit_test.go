@@ -0,0 +1,155 @@
+package bytepool_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_memoryLimit(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	var out bytes.Buffer
+	pool := bytepool.NewBucketOptions([]int{100}, bytepool.BucketPoolOptions{
+		MaxRetained: 10,
+		MemoryLimit: bytepool.MemoryLimit{
+			Soft:     300,
+			Hard:     500,
+			FailGets: true,
+			Logger:   slog.New(slog.NewTextHandler(&out, nil)),
+		},
+	})
+
+	var held []*bytepool.Bytes
+	for range 6 {
+		held = append(held, pool.GetGrown(100))
+	}
+	diffFatal(t, int64(600), pool.Stats().Memory)
+	if _, err := pool.TryGetGrown(100); !errors.Is(err, bytepool.ErrMemoryLimit) {
+		t.Fatal(err)
+	}
+
+	for _, b := range held {
+		b.Release()
+	}
+	// 600 dropped to 500, soft evicts the next two, then the rest are retained.
+	s := pool.Stats()
+	diffFatal(t, [3]int64{300, 1, 2}, [3]int64{s.Memory, int64(s.HardDrops), int64(s.SoftTrims)})
+	diffFatal(t, [2]uint64{1, 2}, [2]uint64{s.Buckets[0].Drops, s.Buckets[0].Trimmed})
+	diffFatal(t, 3, s.Pooled)
+
+	if _, err := pool.TryGetGrown(100); err != nil { // retained
+		t.Fatal(err)
+	}
+
+	logs := out.String()
+	diffFatal(t, [2]int{1, 1}, [2]int{
+		strings.Count(logs, "over soft memory limit"),
+		strings.Count(logs, "over hard memory limit"),
+	})
+
+	pool.Drain()
+	diffFatal(t, int64(100), pool.Stats().Memory) // the one outstanding
+}
+
+func TestBucket_memoryLimitWarnInterval(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	pool := bytepool.NewBucketOptions([]int{100}, bytepool.BucketPoolOptions{
+		MaxRetained: 10,
+		MemoryLimit: bytepool.MemoryLimit{Soft: 150},
+		Logger:      slog.New(slog.NewTextHandler(&out, nil)),
+		LogInterval: time.Hour,
+	})
+
+	for range 3 { // crossing each time.
+		a, b := pool.GetGrown(100), pool.GetGrown(100)
+		a.Release()
+		b.Release()
+		pool.Drain()
+	}
+	diffFatal(t, 1, strings.Count(out.String(), "over soft memory limit"))
+}
+
+func TestBucket_memoryLimitRequiresMaxRetained(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{100}, bytepool.BucketPoolOptions{
+		MemoryLimit: bytepool.MemoryLimit{Hard: 1},
+	})
+	pool.GetGrown(100).Release()
+	diffFatal(t, [2]int64{0, 0}, [2]int64{pool.Stats().Memory, int64(pool.Stats().HardDrops)})
+}
+
+func TestBucket_memoryLimitWeights(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{100, 1000}, bytepool.BucketPoolOptions{
+		MaxRetained: 10,
+		MemoryLimit: bytepool.MemoryLimit{Hard: 2200, Weights: []int{1, 1}}, // 1100 each
+	})
+
+	release := func(size, n int) {
+		var held []*bytepool.Bytes
+		for range n {
+			held = append(held, pool.GetGrown(size))
+		}
+		for _, b := range held {
+			b.Release()
+		}
+	}
+	release(1000, 2) // the first put is over its bucket's 1100 and dropped
+	release(100, 5)
+
+	s := pool.Stats()
+	diffFatal(t, [3]int64{1500, 1, 6}, [3]int64{s.Memory, int64(s.HardDrops), int64(s.Pooled)})
+	diffFatal(t, [2]int64{500, 1000}, [2]int64{s.Buckets[0].Memory, s.Buckets[1].Memory})
+
+	for _, weights := range [][]int{{1}, {1, 0}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected panic", weights)
+				}
+			}()
+			bytepool.NewBucketOptions([]int{100, 1000}, bytepool.BucketPoolOptions{
+				MaxRetained: 10,
+				MemoryLimit: bytepool.MemoryLimit{Hard: 2200, Weights: weights},
+			})
+		}()
+	}
+}
+
+func TestBucket_memoryLimitForeign(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	opts := bytepool.BucketPoolOptions{
+		MaxRetained: 1,
+		MemoryLimit: bytepool.MemoryLimit{Hard: 1000},
+	}
+	pool := bytepool.NewBucketOptions([]int{100}, opts)
+	other := bytepool.NewBucketOptions([]int{100}, opts)
+
+	// retained then dropped as full, counted only while retained.
+	pool.Put(&bytepool.Bytes{B: make([]byte, 100)})
+	pool.Put(&bytepool.Bytes{B: make([]byte, 100)})
+	diffFatal(t, int64(100), pool.Stats().Memory)
+	pool.Drain()
+	diffFatal(t, int64(0), pool.Stats().Memory)
+
+	b := pool.GetGrown(100)
+	pool.Put(&bytepool.Bytes{B: make([]byte, 500)}) // over
+	diffFatal(t, int64(100), pool.Stats().Memory)
+
+	other.Put(b) // moves to other's budget.
+	diffFatal(t, [2]int64{0, 100}, [2]int64{pool.Stats().Memory, other.Stats().Memory})
+}
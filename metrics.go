@@ -0,0 +1,85 @@
+package bytepool
+
+import (
+	"sync/atomic"
+)
+
+// A named pool value, similar to runtime/metrics.
+// Names are stable and formatted as "/bytepool/<path>:<unit>".
+type Metric struct {
+	Name       string
+	Value      uint64
+	Cumulative bool // only increases, otherwise a gauge.
+}
+
+// Implemented by pools that expose metrics, allowing generic collection
+// without knowing concrete stats types.
+type MetricsReader interface {
+	// Appends current metrics to dst.
+	AppendMetrics(dst []Metric) []Metric
+}
+
+func counterMetric(name string, v uint64) Metric {
+	return Metric{Name: name, Value: v, Cumulative: true}
+}
+
+func gaugeMetric(name string, v uint64) Metric {
+	return Metric{Name: name, Value: v}
+}
+
+// Appends:
+//
+//	/bytepool/bucket/gets:calls
+//	/bytepool/bucket/puts:calls
+//	/bytepool/bucket/hits:gets
+//	/bytepool/bucket/misses:gets
+//	/bytepool/bucket/overs:calls
+//	/bytepool/bucket/saved:bytes
+//	/bytepool/bucket/sizes:buckets
+//	/bytepool/bucket/min-size:bytes
+//	/bytepool/bucket/max-size:bytes
+func (p *BucketPool) AppendMetrics(dst []Metric) []Metric {
+	s := p.Stats()
+	return append(dst,
+		counterMetric("/bytepool/bucket/gets:calls", s.Gets),
+		counterMetric("/bytepool/bucket/puts:calls", s.Puts),
+		counterMetric("/bytepool/bucket/hits:gets", s.Hits),
+		counterMetric("/bytepool/bucket/misses:gets", s.Misses),
+		counterMetric("/bytepool/bucket/overs:calls", s.Overs),
+		counterMetric("/bytepool/bucket/saved:bytes", s.SavedBytes),
+		gaugeMetric("/bytepool/bucket/sizes:buckets", uint64(s.Sizes)),
+		gaugeMetric("/bytepool/bucket/min-size:bytes", uint64(s.MinSize)),
+		gaugeMetric("/bytepool/bucket/max-size:bytes", uint64(s.MaxSize)),
+	)
+}
+
+// Appends:
+//
+//	/bytepool/pooler/gets:calls
+//	/bytepool/pooler/hits:gets
+//	/bytepool/pooler/misses:gets
+//	/bytepool/pooler/hits-lookahead:gets
+//	/bytepool/pooler/misses-lookahead:gets
+//	/bytepool/pooler/default-size:bytes
+func (g *BucketPooler) AppendMetrics(dst []Metric) []Metric {
+	s := g.Stats()
+	return append(dst,
+		counterMetric("/bytepool/pooler/gets:calls", s.Gets),
+		counterMetric("/bytepool/pooler/hits:gets", s.Hits),
+		counterMetric("/bytepool/pooler/misses:gets", s.Misses),
+		counterMetric("/bytepool/pooler/hits-lookahead:gets", s.HitsLookahead),
+		counterMetric("/bytepool/pooler/misses-lookahead:gets", s.MissesLookahead),
+		gaugeMetric("/bytepool/pooler/default-size:bytes", uint64(s.DefaultSize)),
+	)
+}
+
+// Appends:
+//
+//	/bytepool/dynamic/default-size:bytes
+//	/bytepool/dynamic/max-size:bytes
+func (p *dynamicPool) AppendMetrics(dst []Metric) []Metric {
+	return append(dst,
+		gaugeMetric("/bytepool/dynamic/default-size:bytes", atomic.LoadUint64(&p.defaultSize)),
+		gaugeMetric("/bytepool/dynamic/max-size:bytes", atomic.LoadUint64(&p.maxSize)),
+	)
+}
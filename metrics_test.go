@@ -0,0 +1,48 @@
+package bytepool_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestMetricsReader(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucket(2, 8)
+	pooler := pool.Pooler(bytepool.BucketPoolerOptions{})
+
+	b := pool.GetGrown(3)
+	diffFatal(t, 4, cap(b.B))
+	b = pool.GetGrown(9)
+	diffFatal(t, 9, cap(b.B))
+
+	readers := []bytepool.MetricsReader{pool, pooler, bytepool.NewDynamic().(bytepool.MetricsReader)}
+
+	var got []bytepool.Metric
+	for _, r := range readers {
+		got = r.AppendMetrics(got)
+	}
+
+	want := []bytepool.Metric{
+		{Name: "/bytepool/bucket/gets:calls", Value: 2, Cumulative: true},
+		{Name: "/bytepool/bucket/puts:calls", Cumulative: true},
+		{Name: "/bytepool/bucket/hits:gets", Cumulative: true},
+		{Name: "/bytepool/bucket/misses:gets", Value: 1, Cumulative: true},
+		{Name: "/bytepool/bucket/overs:calls", Value: 1, Cumulative: true},
+		{Name: "/bytepool/bucket/saved:bytes", Cumulative: true},
+		{Name: "/bytepool/bucket/sizes:buckets", Value: 3},
+		{Name: "/bytepool/bucket/min-size:bytes", Value: 2},
+		{Name: "/bytepool/bucket/max-size:bytes", Value: 8},
+		{Name: "/bytepool/pooler/gets:calls", Cumulative: true},
+		{Name: "/bytepool/pooler/hits:gets", Cumulative: true},
+		{Name: "/bytepool/pooler/misses:gets", Cumulative: true},
+		{Name: "/bytepool/pooler/hits-lookahead:gets", Cumulative: true},
+		{Name: "/bytepool/pooler/misses-lookahead:gets", Cumulative: true},
+		{Name: "/bytepool/pooler/default-size:bytes", Value: 2},
+		{Name: "/bytepool/dynamic/default-size:bytes"},
+		{Name: "/bytepool/dynamic/max-size:bytes"},
+	}
+	diffFatal(t, want, got)
+}
@@ -0,0 +1,123 @@
+package bytepool
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// As NewBucketOptions, erroring rather than panicking on invalid sizes or options.
+// With NoPanics, the pool then never panics on misuse either.
+func TryNewBucket(sizes []int, o BucketPoolOptions) (*BucketPool, error) {
+	if err := checkBucket(sizes, o); err != nil {
+		return nil, tryError(err)
+	}
+	return NewBucketOptions(sizes, o), nil
+}
+
+// As NewBucketFull, erroring rather than panicking on invalid sizes.
+func TryNewBucketFull(sizes []int) (*BucketPool, error) {
+	return TryNewBucket(sizes, BucketPoolOptions{})
+}
+
+// As Pow2Sizes, erroring rather than panicking.
+func TryPow2Sizes(minSize, maxSize int) (SizeSet, error) {
+	if err := checkSizeRange(minSize, maxSize, 1); err != nil {
+		return nil, tryError(err)
+	}
+	return Pow2Sizes(minSize, maxSize), nil
+}
+
+// As LinearSizes, erroring rather than panicking.
+func TryLinearSizes(minSize, maxSize, numBuckets int) (SizeSet, error) {
+	if err := cmp.Or(checkSizeRange(minSize, maxSize, 0), checkNumBuckets(numBuckets)); err != nil {
+		return nil, tryError(err)
+	}
+	return LinearSizes(minSize, maxSize, numBuckets), nil
+}
+
+// As ExpoSizes, erroring rather than panicking.
+func TryExpoSizes(minSize, maxSize, numBuckets int) (SizeSet, error) {
+	if err := cmp.Or(checkSizeRange(minSize, maxSize, 1), checkNumBuckets(numBuckets)); err != nil {
+		return nil, tryError(err)
+	}
+	return ExpoSizes(minSize, maxSize, numBuckets), nil
+}
+
+// As RoundSizesUp, erroring rather than panicking.
+func TryRoundSizesUp(sizes []int, quantum int) (SizeSet, error) {
+	if quantum < 1 {
+		return nil, tryError(errors.New("quantum < 1"))
+	}
+	return RoundSizesUp(sizes, quantum), nil
+}
+
+// Prefixes a check error, which unprefixed serves as a panic message.
+func tryError(err error) error {
+	return fmt.Errorf("bytepool: %w", err)
+}
+
+func checkSizeRange(minSize, maxSize, lowest int) error {
+	if minSize < lowest {
+		return fmt.Errorf("minSize < %d", lowest)
+	}
+	if maxSize <= minSize {
+		return errors.New("maxSize <= minSize")
+	}
+	return nil
+}
+
+func checkNumBuckets(n int) error {
+	if n < 2 {
+		return errors.New("numBuckets < 2")
+	}
+	return nil
+}
+
+func checkBucket(sizes []int, o BucketPoolOptions) error {
+	if len(sizes) == 0 {
+		return errors.New("empty sizes")
+	}
+	for _, s := range sizes {
+		if s < 1 {
+			return fmt.Errorf("size %d < 1", s)
+		}
+	}
+	if w := o.MemoryLimit.Weights; w != nil && max(o.MaxRetained, o.Preallocate) > 0 && (o.MemoryLimit.Soft > 0 || o.MemoryLimit.Hard > 0) {
+		if n := countUnique(sizes); len(w) != n {
+			return fmt.Errorf("%d MemoryLimit Weights for %d buckets", len(w), n)
+		}
+		for _, v := range w {
+			if v <= 0 {
+				return errors.New("MemoryLimit Weights must be positive")
+			}
+		}
+	}
+	return nil
+}
+
+func countUnique(sizes []int) int {
+	seen := make(map[int]struct{}, len(sizes))
+	for _, s := range sizes {
+		seen[s] = struct{}{}
+	}
+	return len(seen)
+}
+
+// Pools with NoPanics.
+type misuser interface {
+	misused(what string) bool
+}
+
+// With NoPanics counts and warns of misuse, returning false when the caller should panic.
+func (p *BucketPool) misused(what string) bool {
+	if !p.noPanics {
+		return false
+	}
+	entry := p.overGate.enter()
+	p.misuses.Add(1)
+	p.overGate.exit(entry)
+	p.warn.warn(warnMisuse, "bytepool misuse", slog.String("misuse", what))
+	return true
+}
@@ -0,0 +1,160 @@
+package bytepool_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestTryNewBucket(t *testing.T) {
+	t.Parallel()
+
+	invalid := []struct {
+		sizes []int
+		o     bytepool.BucketPoolOptions
+	}{
+		{nil, bytepool.BucketPoolOptions{}},
+		{[]int{8, 0}, bytepool.BucketPoolOptions{}},
+		{[]int{8, 16}, bytepool.BucketPoolOptions{MaxRetained: 1, MemoryLimit: bytepool.MemoryLimit{Hard: 100, Weights: []int{1}}}},
+		{[]int{8, 16}, bytepool.BucketPoolOptions{MaxRetained: 1, MemoryLimit: bytepool.MemoryLimit{Hard: 100, Weights: []int{1, 0}}}},
+	}
+	for _, c := range invalid {
+		if _, err := bytepool.TryNewBucket(c.sizes, c.o); err == nil {
+			t.Fatal("expected error", c.sizes, c.o.MemoryLimit)
+		}
+	}
+
+	pool, err := bytepool.TryNewBucket([]int{16, 8, 16}, bytepool.BucketPoolOptions{
+		MaxRetained: 1,
+		MemoryLimit: bytepool.MemoryLimit{Hard: 100, Weights: []int{1, 1}}, // per bucket, after repeats.
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, bytepool.SizeSet{8, 16}, pool.Sizes())
+
+	func() {
+		defer func() {
+			diffFatal(t, any("empty sizes"), recover()) // plain string, unlike the error.
+		}()
+		bytepool.NewBucketFull(nil)
+	}()
+}
+
+func TestTrySizes(t *testing.T) {
+	t.Parallel()
+
+	errs := []error{
+		second(bytepool.TryPow2Sizes(0, 8)),
+		second(bytepool.TryPow2Sizes(8, 8)),
+		second(bytepool.TryLinearSizes(-1, 8, 2)),
+		second(bytepool.TryLinearSizes(0, 8, 1)),
+		second(bytepool.TryExpoSizes(0, 8, 2)),
+		second(bytepool.TryExpoSizes(1, 8, 1)),
+		second(bytepool.TryRoundSizesUp([]int{8}, 0)),
+		second(bytepool.TryNewBucketFull([]int{0})),
+	}
+	for i, err := range errs {
+		if err == nil {
+			t.Fatal("expected error", i)
+		}
+	}
+
+	sizes, err := bytepool.TryPow2Sizes(8, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, bytepool.Pow2Sizes(8, 32), sizes)
+
+	sizes, err = bytepool.TryLinearSizes(0, 30, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, bytepool.LinearSizes(0, 30, 4), sizes)
+
+	sizes, err = bytepool.TryExpoSizes(8, 64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, bytepool.ExpoSizes(8, 64, 4), sizes)
+
+	sizes, err = bytepool.TryRoundSizesUp([]int{9, 70}, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, bytepool.SizeSet{64, 128}, sizes)
+
+	pool, err := bytepool.TryNewBucketFull([]int{8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, bytepool.SizeSet{8}, pool.Sizes())
+}
+
+func second[T any](_ T, err error) error {
+	return err
+}
+
+func TestBucket_noPanics(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8, 16}, bytepool.BucketPoolOptions{NoPanics: true})
+
+	b := pool.GetGrownAtMost(10, 4)
+	diffFatal(t, 10, cap(b.B)) // capped at want.
+	b.Release()
+
+	b = pool.GetBetween(5, 1)
+	diffFatal(t, 5, cap(b.B))
+	b.Release()
+
+	r, err := pool.Reserve(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Get().Release()
+	b = r.Get()
+	diffFatal(t, 8, cap(b.B))
+	b.Release()
+
+	diffFatal(t, uint64(3), pool.Stats().Misuses)
+	pool.ResetStats()
+	diffFatal(t, uint64(0), pool.Stats().Misuses)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic without NoPanics")
+			}
+		}()
+		bytepool.NewBucketFull([]int{8}).GetBetween(5, 1)
+	}()
+}
+
+func TestArena_putView(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{64}, bytepool.BucketPoolOptions{MaxRetained: 4, NoPanics: true})
+	a := bytepool.NewArena(pool, 64)
+	b := a.GetGrown(8)
+
+	requirePanic(t, func() { bytepool.NewSync().Put(b) })
+	pool.Put(b) // stays in the Arena.
+	diffFatal(t, uint64(1), pool.Stats().Misuses)
+	diffFatal(t, 0, pool.Stats().Pooled)
+
+	a.Free()
+	diffFatal(t, 1, pool.Stats().Pooled) // the slab.
+}
+
+func requirePanic(t testing.TB, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	f()
+}
@@ -0,0 +1,40 @@
+//go:build linux
+
+package bytepool
+
+import (
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// Uses of a cached node before calling getcpu again.
+const nodeRefresh = 1024
+
+// Per P, as the node of a P's thread rarely changes.
+var nodeCache = sync.Pool{New: func() any { return new(cachedNode) }}
+
+type cachedNode struct {
+	node, uses int
+}
+
+// NUMA node of the calling thread, 0 when unknown. Cached, so possibly a recent node.
+func currentNode() int {
+	c := nodeCache.Get().(*cachedNode)
+	if c.uses == 0 {
+		c.node = getcpuNode()
+	}
+	c.uses = (c.uses + 1) % nodeRefresh
+	node := c.node
+	nodeCache.Put(c)
+	return node
+}
+
+func getcpuNode() int {
+	var cpu, node uint32
+	_, _, errno := syscall.RawSyscall(sysGetcpu, uintptr(unsafe.Pointer(&cpu)), uintptr(unsafe.Pointer(&node)), 0)
+	if errno != 0 {
+		return 0
+	}
+	return int(node)
+}
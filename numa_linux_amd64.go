@@ -0,0 +1,4 @@
+package bytepool
+
+// missing from syscall on amd64.
+const sysGetcpu = 309
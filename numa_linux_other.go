@@ -0,0 +1,7 @@
+//go:build linux && !amd64
+
+package bytepool
+
+import "syscall"
+
+const sysGetcpu = syscall.SYS_GETCPU
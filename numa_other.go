@@ -0,0 +1,8 @@
+//go:build !linux
+
+package bytepool
+
+// NUMA node of the calling thread, always 0 as only supported on Linux.
+func currentNode() int {
+	return 0
+}
@@ -0,0 +1,66 @@
+package bytepool
+
+// Implemented by pools that can get without allocating, such as BucketPool.
+type RetainedPooler interface {
+	SizedPooler
+
+	// Retained Bytes with zero length and minimum capacity c, or nil rather than allocating.
+	GetRetained(c int) *Bytes
+}
+
+// Gets from a primary pool's retained Bytes, falling back to a secondary on a miss, such as
+// a strictly bounded pool over a shared one. Bytes release to whichever pool they came from.
+type Overflow struct {
+	primary   RetainedPooler
+	secondary SizedPooler // nil for plain allocation.
+
+	gate      statsGate
+	gets      counter
+	fallbacks counter
+}
+
+// secondary can be nil to allocate on a miss, such Bytes are left to the GC on Release.
+func NewOverflow(primary RetainedPooler, secondary SizedPooler) *Overflow {
+	return &Overflow{primary: primary, secondary: secondary}
+}
+
+func (o *Overflow) GetGrown(c int) *Bytes {
+	b := o.primary.GetRetained(c)
+
+	entry := o.gate.enter()
+	o.gets.Add(1)
+	if b == nil {
+		o.fallbacks.Add(1)
+	}
+	o.gate.exit(entry)
+
+	if b != nil {
+		return b
+	}
+	if o.secondary == nil {
+		return &Bytes{B: make([]byte, 0, c), zeroed: true}
+	}
+	return o.secondary.GetGrown(c)
+}
+
+func (o *Overflow) GetFilled(length int) *Bytes {
+	b := o.GetGrown(length)
+	b.B = b.B[:length]
+	return b
+}
+
+type OverflowStats struct {
+	Gets      uint64
+	Fallbacks uint64 // Gets served by the secondary.
+}
+
+func (o *Overflow) Stats() OverflowStats {
+	var s OverflowStats
+	readGates(func() {
+		s = OverflowStats{
+			Gets:      o.gets.Load(),
+			Fallbacks: o.fallbacks.Load(),
+		}
+	}, &o.gate)
+	return s
+}
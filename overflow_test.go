@@ -0,0 +1,40 @@
+package bytepool_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestOverflow(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	primary := bytepool.NewBucketOptions([]int{8, 16}, bytepool.BucketPoolOptions{MaxRetained: 1})
+	secondary := bytepool.NewBucket(8, 64)
+	o := bytepool.NewOverflow(primary, secondary)
+
+	b1 := o.GetGrown(8) // miss, from secondary
+	b1.Release()
+	diffFatal(t, uint64(1), secondary.Stats().Puts)
+
+	primary.GetGrown(8).Release()
+	b2 := o.GetFilled(5) // primary hit
+	diffFatal(t, [2]int{5, 8}, [2]int{len(b2.B), cap(b2.B)})
+	b2.Release()
+	diffFatal(t, uint64(2), primary.Stats().Puts)
+
+	b3 := o.GetGrown(20) // over primary
+	diffFatal(t, 32, cap(b3.B))
+	b3.Release()
+
+	diffFatal(t, bytepool.OverflowStats{Gets: 3, Fallbacks: 2}, o.Stats())
+
+	alloc := bytepool.NewOverflow(primary, nil)
+	b := alloc.GetGrown(100)
+	diffFatal(t, 100, cap(b.B))
+	b.Release() // dropped
+	if primary.GetRetained(100) != nil {
+		t.Fatal("retained over")
+	}
+}
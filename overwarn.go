@@ -0,0 +1,48 @@
+package bytepool
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// warns once per interval when overs within it exceed threshold.
+type overWarner struct {
+	warn      *warner
+	interval  time.Duration
+	threshold int
+
+	mu      sync.Mutex
+	start   time.Time
+	count   int
+	largest int
+	warned  bool
+}
+
+func (w *overWarner) observe(over, maxSize int) {
+	now := time.Now()
+
+	w.mu.Lock()
+	if now.Sub(w.start) >= w.interval {
+		w.start = now
+		w.count = 0
+		w.largest = 0
+		w.warned = false
+	}
+	w.count++
+	w.largest = max(w.largest, over)
+	if w.warned || w.count <= w.threshold {
+		w.mu.Unlock()
+		return
+	}
+	w.warned = true
+	count, largest := w.count, w.largest
+	w.mu.Unlock()
+
+	w.warn.warn(warnOvers, "bytepool overs exceeded threshold",
+		slog.Int("overs", count),
+		slog.Duration("interval", w.interval),
+		slog.Int("max_size", maxSize),
+		slog.Int("largest_over", largest),
+	)
+}
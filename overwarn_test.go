@@ -0,0 +1,52 @@
+package bytepool_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_overWarn(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	pool := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{
+		Name:              "test",
+		OverWarnLogger:    slog.New(slog.NewJSONHandler(&out, nil)),
+		OverWarnInterval:  time.Hour,
+		OverWarnThreshold: 2,
+	})
+
+	pool.GetGrown(9)
+	pool.GetGrown(10)
+	diffFatal(t, "", out.String())
+
+	for range 10 {
+		pool.GetGrown(40)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	diffFatal(t, 1, len(lines)) // once per interval
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatal(err)
+	}
+	delete(got, "time")
+	want := map[string]any{
+		"level":        "WARN",
+		"msg":          "bytepool overs exceeded threshold",
+		"pool":         "test",
+		"overs":        float64(3),
+		"interval":     float64(time.Hour),
+		"max_size":     float64(8),
+		"largest_over": float64(40),
+		"suppressed":   float64(0),
+	}
+	diffFatal(t, want, got)
+}
@@ -0,0 +1,97 @@
+package bytepool
+
+type PacketPoolOptions struct {
+	MTU         int // packet capacity. Defaults to 1500.
+	MaxRetained int // Defaults to 1024.
+}
+
+// Fixed size packets for datagram workloads, with batches aligned to ReadBatch and WriteBatch
+// of golang.org/x/net/ipv4 and ipv6, such as:
+//
+//	pp.GetBatch(bufs)
+//	for i := range msgs {
+//		msgs[i].Buffers[0] = bufs[i].B
+//	}
+//	n, err := conn.ReadBatch(msgs, 0)
+//	for i, m := range msgs[:n] {
+//		pp.Received(bufs[i], m.N, m.Flags&unix.MSG_TRUNC != 0)
+//		...
+//	}
+type PacketPool struct {
+	pool *BucketPool
+	mtu  int
+
+	gate          statsGate
+	received      counter
+	receivedBytes counter
+	truncated     counter
+}
+
+func NewPacketPool(o PacketPoolOptions) *PacketPool {
+	if o.MTU <= 0 {
+		o.MTU = 1500
+	}
+	if o.MaxRetained <= 0 {
+		o.MaxRetained = 1024
+	}
+	return &PacketPool{
+		pool: NewBucketOptions([]int{o.MTU}, BucketPoolOptions{MaxRetained: o.MaxRetained}),
+		mtu:  o.MTU,
+	}
+}
+
+// Packet with length MTU, ready to read into.
+func (p *PacketPool) Get() *Bytes {
+	return p.pool.GetFilled(p.mtu)
+}
+
+// Fills nil entries of bufs with packets and restores the others to length MTU, so entries
+// not kept after a read are reused by the next.
+func (p *PacketPool) GetBatch(bufs []*Bytes) {
+	for i, b := range bufs {
+		if b == nil {
+			bufs[i] = p.Get()
+		} else {
+			b.B = b.B[:p.mtu]
+		}
+	}
+}
+
+// Releases every non-nil entry of bufs, setting it nil, such as after WriteBatch.
+func (p *PacketPool) PutBatch(bufs []*Bytes) {
+	for i, b := range bufs {
+		b.Release()
+		bufs[i] = nil
+	}
+}
+
+// Records a read of n bytes into b, truncating b.B to n. truncated is whether the datagram
+// was larger than b, such as from MSG_TRUNC.
+func (p *PacketPool) Received(b *Bytes, n int, truncated bool) {
+	b.B = b.B[:n]
+
+	entry := p.gate.enter()
+	p.received.Add(1)
+	p.receivedBytes.Add(uint64(n))
+	if truncated {
+		p.truncated.Add(1)
+	}
+	p.gate.exit(entry)
+}
+
+type PacketPoolStats struct {
+	BucketPoolStats
+	Received      uint64
+	ReceivedBytes uint64
+	Truncated     uint64 // received larger than MTU, raise MTU when positive.
+}
+
+func (p *PacketPool) Stats() PacketPoolStats {
+	s := PacketPoolStats{BucketPoolStats: p.pool.Stats()}
+	readGates(func() {
+		s.Received = p.received.Load()
+		s.ReceivedBytes = p.receivedBytes.Load()
+		s.Truncated = p.truncated.Load()
+	}, &p.gate)
+	return s
+}
@@ -0,0 +1,65 @@
+package bytepool_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestPacketPool(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pp := bytepool.NewPacketPool(bytepool.PacketPoolOptions{MTU: 100})
+
+	bufs := make([]*bytepool.Bytes, 3)
+	pp.GetBatch(bufs)
+	for _, b := range bufs {
+		diffFatal(t, [2]int{100, 100}, [2]int{len(b.B), cap(b.B)})
+	}
+
+	pp.Received(bufs[0], 10, false)
+	pp.Received(bufs[1], 100, true)
+	diffFatal(t, 10, len(bufs[0].B))
+
+	kept := bufs[0]
+	bufs[0] = nil
+	pp.GetBatch(bufs) // refills taken and restores others
+	if bufs[0] == nil || bufs[0] == kept {
+		t.Fatal(bufs[0])
+	}
+	diffFatal(t, 100, len(bufs[1].B))
+
+	kept.Release()
+	pp.PutBatch(bufs)
+	diffFatal(t, []*bytepool.Bytes{nil, nil, nil}, bufs)
+
+	s := pp.Stats()
+	diffFatal(t, [3]uint64{2, 110, 1}, [3]uint64{s.Received, s.ReceivedBytes, s.Truncated})
+	diffFatal(t, [2]int64{0, 4}, [2]int64{s.Outstanding, int64(s.Pooled)})
+}
+
+func TestPacketPool_udp(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skip(err)
+	}
+	defer conn.Close()
+
+	pp := bytepool.NewPacketPool(bytepool.PacketPoolOptions{})
+	if _, err := conn.WriteToUDP([]byte("ping"), conn.LocalAddr().(*net.UDPAddr)); err != nil {
+		t.Fatal(err)
+	}
+
+	b := pp.Get()
+	n, _, err := conn.ReadFromUDP(b.B)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pp.Received(b, n, false)
+	diffFatal(t, "ping", string(b.B))
+	b.Release()
+}
@@ -0,0 +1,165 @@
+package bytepool
+
+import (
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+type PinnedOptions struct {
+	MaxRetained int // Bytes each size retains, further releases are freed. Defaults to 64.
+}
+
+// For memory handed to the kernel or C beyond a call, such as io_uring registered buffers or
+// long lived cgo calls. Backing arrays are never moved or freed while leased: on Linux they are
+// mmap'd off the Go heap and locked where permitted, elsewhere they are Go heap arrays pinned
+// with runtime.Pinner by Pin. Bytes never migrate to other pools, as SecurePool.
+// Each array is rounded up to a page on Linux, so prefer few sizes.
+type PinnedPool struct {
+	pool    *BucketPool
+	alloc   *lockedAllocator
+	maxSize int
+
+	mu     sync.Mutex
+	pinned map[*Bytes]*pin
+	pins   uint64
+	unpins uint64
+}
+
+type pin struct {
+	n      int
+	pinner runtime.Pinner // unused for off-heap arrays.
+}
+
+type PinnedPoolStats struct {
+	BucketPoolStats
+	LockFailures uint64 // allocations not locked, such as when over RLIMIT_MEMLOCK.
+	Pins         uint64
+	Unpins       uint64
+	Pinned       int // Bytes currently pinned.
+}
+
+// sizes as NewBucketFull.
+func NewPinned(sizes []int, o PinnedOptions) *PinnedPool {
+	if o.MaxRetained <= 0 {
+		o.MaxRetained = 64
+	}
+	alloc := newLockedAllocator()
+	pool := NewBucketOptions(sizes, BucketPoolOptions{
+		Allocator:   alloc,
+		MaxRetained: o.MaxRetained, // no GC drops.
+	})
+	return &PinnedPool{
+		pool:    pool,
+		alloc:   alloc,
+		maxSize: pool.pools[len(pool.pools)-1].size,
+		pinned:  make(map[*Bytes]*pin),
+	}
+}
+
+func (p *PinnedPool) GetGrown(c int) *Bytes {
+	var b *Bytes
+	if c > p.maxSize {
+		p.pool.over(c, false)
+		b = allocSizedBytes(p.alloc, c, p, nil) // pinnable too, unlike BucketPool overs.
+	} else {
+		b = p.pool.GetGrown(c)
+	}
+	b.pool = p
+	return b
+}
+
+func (p *PinnedPool) GetFilled(length int) *Bytes {
+	b := p.GetGrown(length)
+	b.B = b.B[:length]
+	return b
+}
+
+// Start of b's backing array, stable until a matching Unpin. Pins nest.
+// Releasing b while pinned panics. b must be from this pool with B unreplaced.
+func (p *PinnedPool) Pin(b *Bytes) unsafe.Pointer {
+	if !p.alloc.owns(b.B) {
+		panic("pin of Bytes not from PinnedPool")
+	}
+	data := unsafe.SliceData(b.B[:cap(b.B)])
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pn := p.pinned[b]
+	if pn == nil {
+		pn = &pin{}
+		if p.alloc.heap(b.B) {
+			pn.pinner.Pin(data)
+		}
+		p.pinned[b] = pn
+	}
+	pn.n++
+	p.pins++
+	return unsafe.Pointer(data)
+}
+
+// Undoes a Pin. Panics if b is not pinned.
+func (p *PinnedPool) Unpin(b *Bytes) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pn := p.pinned[b]
+	if pn == nil {
+		panic("unpin of Bytes not pinned")
+	}
+	p.unpins++
+	if pn.n--; pn.n == 0 {
+		pn.pinner.Unpin()
+		delete(p.pinned, b)
+	}
+}
+
+// As BucketPool.Sizes.
+func (p *PinnedPool) Sizes() SizeSet {
+	return p.pool.Sizes()
+}
+
+func (p *PinnedPool) bucketMax() int {
+	return p.maxSize
+}
+
+func (p *PinnedPool) bucketFit(c int) int {
+	return p.pool.bucketFit(c)
+}
+
+func (p *PinnedPool) Stats() PinnedPoolStats {
+	s := PinnedPoolStats{
+		BucketPoolStats: p.pool.Stats(),
+		LockFailures:    p.alloc.lockFailures.Load(),
+	}
+	p.mu.Lock()
+	s.Pins, s.Unpins, s.Pinned = p.pins, p.unpins, len(p.pinned)
+	p.mu.Unlock()
+	return s
+}
+
+func (p *PinnedPool) put(b *Bytes) {
+	if b == nil {
+		return
+	}
+	p.mu.Lock()
+	_, pinned := p.pinned[b]
+	p.mu.Unlock()
+	if pinned {
+		panic("release of pinned Bytes")
+	}
+
+	region, ok := p.alloc.region(b.base)
+	if !ok || unsafe.SliceData(b.B[:cap(b.B)]) != b.base {
+		b.pool = nil // replaced B, leaving the original to the finalizer.
+		return
+	}
+	b.B = region // as B may have been resliced to a smaller cap.
+	if cap(region) > p.maxSize {
+		p.pool.over(cap(region), true)
+		freeAllocated(p.alloc, b, cap(region))
+		return
+	}
+	p.pool.put(b)
+}
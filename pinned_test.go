@@ -0,0 +1,72 @@
+package bytepool_test
+
+import (
+	"runtime"
+	"testing"
+	"unsafe"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestPinned(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewPinned([]int{64, 4096}, bytepool.PinnedOptions{})
+
+	b := pool.GetFilled(10)
+	ptr := pool.Pin(b)
+	if ptr != unsafe.Pointer(unsafe.SliceData(b.B)) {
+		t.Fatal("pointer")
+	}
+	pool.Pin(b) // nests
+	runtime.GC()
+	pool.Unpin(b)
+
+	s := pool.Stats()
+	diffFatal(t, [3]uint64{2, 1, 1}, [3]uint64{s.Pins, s.Unpins, uint64(s.Pinned)})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected release panic")
+			}
+		}()
+		b.Release()
+	}()
+
+	pool.Unpin(b)
+	b.Release()
+
+	b = pool.GetFilled(64) // retained by the free list, not dropped by GC.
+	if unsafe.Pointer(unsafe.SliceData(b.B)) != ptr {
+		t.Fatal("not reused")
+	}
+
+	other := bytepool.NewBucket(8, 64)
+	other.Adopt(b) // stays
+	b.Release()
+	diffFatal(t, uint64(0), other.Stats().Puts)
+
+	over := pool.GetGrown(5000)
+	pool.Pin(over)
+	pool.Unpin(over)
+	over.Release()
+
+	s = pool.Stats()
+	diffFatal(t, [4]uint64{3, 3, 0, 2}, [4]uint64{s.Pins, s.Unpins, uint64(s.Pinned), s.Overs})
+
+	over = pool.GetGrown(5000)
+	over.B = over.B[:0:10] // still freed as an over.
+	over.Release()
+	diffFatal(t, uint64(4), pool.Stats().Overs)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected unpin panic")
+			}
+		}()
+		pool.Unpin(pool.GetGrown(1))
+	}()
+}
@@ -1,22 +1,51 @@
 package bytepool
 
+import (
+	"unsafe"
+)
+
 // using *Bytes vs []byte or *[]byte, as we need to allow mutation
 // of the pointed item, but giving the original pointer back to the
 // to avoid an extra allocation.
 
 type Bytes struct {
-	B    []byte
+	B    []byte // first, see BytesOf.
 	pool poolPutter
+
+	base    *byte    // start of the array from an Allocator, whatever B is made to point at.
+	counted memCount // against a MemoryLimit.
+	zeroed  bool     // B[:cap(B)] known zero when handed out by a pool, reset on put.
+	view    bool     // B is within memory owned by another, from an Arena, Tokenizer or SharedPool.
+	lost    lostKind // what losing b would be, with GCReport.
+	debug   debugState
 }
 
 // Release returns the Bytes to the pool it came from.
 // Do not use Bytes after calling Release.
 func (b *Bytes) Release() {
 	if b != nil && b.pool != nil {
+		if _, shared := b.pool.(*SharedPool); !shared { // poisoned by put, while mapped.
+			b.debug.released(b.B)
+		}
 		b.pool.put(b)
 	}
 }
 
+// The Bytes of p, which must be &b.B of a Bytes b. Allows APIs pooling *[]byte,
+// such as gRPC's mem.BufferPool, to release what they were given.
+func BytesOf(p *[]byte) *Bytes {
+	return (*Bytes)(unsafe.Pointer(p))
+}
+
+// String view of B without copying, valid until Release.
+// B must not be modified while the view is used.
+// With the bytepool_debug build tag, Release panics if B was modified and poisons the
+// released memory so views used after Release are visibly corrupt.
+func (b *Bytes) UnsafeString() string {
+	b.debug.viewed(b.B)
+	return unsafe.String(unsafe.SliceData(b.B), len(b.B))
+}
+
 type poolPutter interface {
 	put(*Bytes)
 }
@@ -39,6 +68,46 @@ type Pooler interface {
 	SizedPooler
 }
 
+type SizedPool interface {
+	SizedPooler
+
+	// Returns b to this pool, wherever it came from, so later Releases also target this pool.
+	// Bytes from a SecurePool, PinnedPool or GetLeased are returned to it instead. Panics on
+	// views of another's memory, from an Arena, Tokenizer or SharedPool slot, or with NoPanics
+	// returns them where they came from. Do not use b after calling Put.
+	Put(b *Bytes)
+
+	// Makes this pool the origin of b, so Release returns b to this pool.
+	// Bytes from a SecurePool, PinnedPool or GetLeased are left as is. Panics on views as Put,
+	// or with NoPanics leaves them as is.
+	Adopt(b *Bytes)
+}
+
+type Pool interface {
+	Pooler
+
+	// Returns b to this pool, wherever it came from, so later Releases also target this pool.
+	// Bytes from a SecurePool, PinnedPool or GetLeased are returned to it instead. Panics on
+	// views of another's memory, from an Arena, Tokenizer or SharedPool slot, or with NoPanics
+	// returns them where they came from. Do not use b after calling Put.
+	Put(b *Bytes)
+
+	// Makes this pool the origin of b, so Release returns b to this pool.
+	// Bytes from a SecurePool, PinnedPool or GetLeased are left as is. Panics on views as Put,
+	// or with NoPanics leaves them as is.
+	Adopt(b *Bytes)
+}
+
+// Implemented by pools that calibrate sizes from their Releases, such as BucketPooler and
+// NewDynamic, to lock in a warmed calibration before a latency critical window.
+type Calibrator interface {
+	// Stops calibrating, keeping current sizes. Releases are not recorded while frozen.
+	Freeze()
+
+	// Resumes calibrating from the state at Freeze.
+	Unfreeze()
+}
+
 // Ensures capacity for min total elements.
 // Min can be <= 0.
 // Returned slice has len=0.
@@ -54,6 +123,29 @@ func Grow[T any](s []T, min int) []T {
 	return append(s[:cap(s)], make([]T, min-c)...)[:0]
 }
 
+// Same as Grow but returned slice has cap=c exactly, allocating at most once.
+// Capacity over c is hidden by reslicing. c can be <= 0.
+func GrowExact[T any](s []T, c int) []T {
+	c = max(0, c)
+	if c <= cap(s) {
+		return s[:0:c]
+	}
+	n := make([]T, c)
+	copy(n, s[:cap(s)])
+	return n[:0]
+}
+
+// Ensures capacity for min total elements, preserving len and contents.
+// Min can be <= 0.
+func GrowKeep[T any](s []T, min int) []T {
+	c := cap(s)
+	if min <= c {
+		return s
+	}
+	// same single allocation as Grow.
+	return append(s[:c], make([]T, min-c)...)[:len(s)]
+}
+
 // Returns s if cap(s) >= size, otherwise makes a new slice with cap=size.
 // New slice does not preserve contents of s.
 // Size can be <= 0.
@@ -64,3 +156,123 @@ func Sized[T any](s []T, size int) []T {
 	}
 	return make([]T, 0, size)
 }
+
+// Same as Sized but preserves the first min(len(s), size) elements.
+// Returned slice has len=min(len(s), size).
+func SizedKeep[T any](s []T, size int) []T {
+	if size <= cap(s) {
+		return s[:max(0, min(len(s), size))]
+	}
+	n := make([]T, len(s), size)
+	copy(n, s)
+	return n
+}
+
+// Returns s if cap(s) <= maxCap, otherwise copies s to a new slice with cap=len(s).
+// Preserves len and contents.
+func Shrink[T any](s []T, maxCap int) []T {
+	if cap(s) <= maxCap {
+		return s
+	}
+	n := make([]T, len(s))
+	copy(n, s)
+	return n
+}
+
+// Bytes from p with a copy of src.
+func CopyFrom(p SizedPooler, src []byte) *Bytes {
+	b := p.GetFilled(len(src))
+	copy(b.B, src)
+	return b
+}
+
+// As CopyFrom with a string.
+func CopyFromString(p SizedPooler, src string) *Bytes {
+	b := p.GetFilled(len(src))
+	copy(b.B, src)
+	return b
+}
+
+// Returns b if cap(b.B) >= c, otherwise copies b.B into Bytes from p with cap >= c and releases b,
+// such as to grow without append reallocating outside the pool. Preserves len and contents.
+// b can be nil.
+func GrowPooled(p SizedPooler, b *Bytes, c int) *Bytes {
+	if b == nil {
+		return p.GetGrown(c)
+	}
+	if c <= cap(b.B) {
+		return b
+	}
+	nb := p.GetGrown(growCap(p, cap(b.B), c))
+	nb.B = append(nb.B, b.B...)
+	b.Release()
+	return nb
+}
+
+// Capacity to grow from have to at least c. Within p's buckets their sizes amortize growth,
+// past them or without buckets doubles as append does.
+func growCap(p SizedPooler, have, c int) int {
+	if m, ok := p.(bucketSizer); ok && c <= m.bucketMax() {
+		return c
+	}
+	return max(c, 2*have)
+}
+
+// Pools with buckets.
+type bucketSizer interface {
+	bucketMax() int      // size of the largest bucket.
+	bucketFit(c int) int // capacity GetGrown(c) returns, c when over the buckets.
+}
+
+// Appends data to b.B, regrowing through p rather than append: when over cap, data and the
+// contents of b go into larger Bytes from p and b is released. Returns the Bytes to use from then on.
+// Past p's buckets capacity doubles, so appending a byte at a time stays amortized. b can be nil.
+func AppendPooled(p SizedPooler, b *Bytes, data ...byte) *Bytes {
+	b = GrowPooled(p, b, bytesLen(b)+len(data))
+	b.B = append(b.B, data...)
+	return b
+}
+
+// As AppendPooled with a string.
+func AppendStringPooled(p SizedPooler, b *Bytes, s string) *Bytes {
+	b = GrowPooled(p, b, bytesLen(b)+len(s))
+	b.B = append(b.B, s...)
+	return b
+}
+
+func bytesLen(b *Bytes) int {
+	if b == nil {
+		return 0
+	}
+	return len(b.B)
+}
+
+// Returns b if cap(b.B) <= maxCap, otherwise copies b.B into Bytes from p sized for len(b.B)
+// and releases b. Preserves len and contents.
+// b cannot be nil.
+func ShrinkPooled(p SizedPooler, b *Bytes, maxCap int) *Bytes {
+	if cap(b.B) <= maxCap {
+		return b
+	}
+	nb := p.GetFilled(len(b.B))
+	copy(nb.B, b.B)
+	b.Release()
+	return nb
+}
+
+// Copies b.B into Bytes from p sized for len(b.B) if their cap is smaller than b's, releasing b,
+// such as before retaining b for long. Otherwise returns b. Preserves len and contents.
+// Pools with buckets are checked without a Get. b cannot be nil.
+func ShrinkToFit(p SizedPooler, b *Bytes) *Bytes {
+	if s, ok := p.(bucketSizer); ok && s.bucketFit(len(b.B)) >= cap(b.B) {
+		return b
+	}
+	nb := p.GetFilled(len(b.B))
+	if cap(nb.B) >= cap(b.B) {
+		nb.Release()
+		return b
+	}
+	copy(nb.B, b.B)
+	b.Release()
+	return nb
+}
@@ -6,8 +6,10 @@ import (
 	"sync"
 	"testing"
 	"time"
+	"unsafe"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/graxinc/bytepool"
 )
 
@@ -114,6 +116,100 @@ func TestSizedPooler_lenAndCap(t *testing.T) {
 	})
 }
 
+func TestBytes_releaseReuses(t *testing.T) {
+	t.Parallel()
+
+	run := func(t *testing.T, pool bytepool.SizedPooler) {
+		for range 1000 { // can have a buf dropped sometimes
+			b1 := pool.GetGrown(8)
+			b1.Release()
+			b2 := pool.GetGrown(8)
+			b2.Release()
+			if b1 == b2 {
+				return
+			}
+		}
+		t.Fatal("not reused")
+	}
+	t.Run("sync", func(t *testing.T) {
+		run(t, bytepool.NewSync())
+	})
+	t.Run("dynamic", func(t *testing.T) {
+		run(t, bytepool.NewDynamic())
+	})
+	t.Run("bucket", func(t *testing.T) {
+		run(t, bytepool.NewBucket(1, 20))
+	})
+	t.Run("bucket_pooler", func(t *testing.T) {
+		pool := bytepool.NewBucket(1, 20)
+		run(t, pool.Pooler(bytepool.BucketPoolerOptions{}))
+	})
+}
+
+func TestPool_Put(t *testing.T) {
+	t.Parallel()
+
+	run := func(t *testing.T, pool bytepool.SizedPool) {
+		other := bytepool.NewSync()
+
+		for range 1000 { // can have a buf dropped sometimes
+			b1 := other.GetGrown(8)
+			pool.Put(b1)
+			pool.Put(nil)
+
+			b2 := pool.GetGrown(8)
+			if b1 != b2 {
+				b2.Release()
+				continue
+			}
+
+			b2.Release() // back to pool, not other
+			b3 := pool.GetGrown(8)
+			if b3 == b2 {
+				return
+			}
+		}
+		t.Fatal("not reused")
+	}
+	t.Run("sync", func(t *testing.T) {
+		run(t, bytepool.NewSync())
+	})
+	t.Run("dynamic", func(t *testing.T) {
+		run(t, bytepool.NewDynamic())
+	})
+	t.Run("bucket", func(t *testing.T) {
+		run(t, bytepool.NewBucket(1, 20))
+	})
+	t.Run("bucket_pooler", func(t *testing.T) {
+		pool := bytepool.NewBucket(1, 20)
+		var _ bytepool.Pool = pool.Pooler(bytepool.BucketPoolerOptions{})
+		run(t, pool.Pooler(bytepool.BucketPoolerOptions{}))
+	})
+}
+
+func TestPool_Adopt(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	global := bytepool.NewBucket(1, 20)
+	conn := global.Pooler(bytepool.BucketPoolerOptions{})
+
+	b := conn.Get()
+	b.B = append(b.B, 1, 2, 3)
+	global.Adopt(b)
+	global.Adopt(nil)
+	diffFatal(t, []byte{1, 2, 3}, b.B)
+	b.Release() // not counted as a conn put
+
+	want := bytepool.BucketPoolerStats{
+		Bins:        []bytepool.BinStats{{Size: 1, Misses: 1}},
+		DefaultSize: 1,
+		Gets:        1,
+		Misses:      1,
+	}
+	diffFatal(t, want, conn.Stats())
+}
+
 func TestBytes_nilRelease(t *testing.T) {
 	t.Parallel()
 
@@ -121,6 +217,15 @@ func TestBytes_nilRelease(t *testing.T) {
 	b.Release()
 }
 
+func TestBytesOf(t *testing.T) {
+	t.Parallel()
+
+	b := bytepool.NewBucket(8, 16).GetGrown(8)
+	if got := bytepool.BytesOf(&b.B); got != b {
+		t.Fatal(got)
+	}
+}
+
 func TestGrowAllocs(t *testing.T) {
 	do := func(n1, n2 int) {
 		buf := make([]byte, n1)
@@ -216,6 +321,91 @@ func TestGrow(t *testing.T) {
 	})
 }
 
+func TestGrowExact(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		v []byte
+		c int
+	}{
+		{nil, -1},
+		{nil, 0},
+		{nil, 9},
+		{[]byte{1}, 0},
+		{[]byte{1}, 1},
+		{[]byte{1}, 9},
+		{[]byte{1, 2, 3}, 2},
+		{[]byte{1, 2, 3}, 33},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("v=%v,c=%v", c.v, c.c), func(t *testing.T) {
+			got := bytepool.GrowExact(c.v, c.c)
+			diffFatal(t, 0, len(got))
+			diffFatal(t, max(0, c.c), cap(got))
+
+			n := min(len(c.v), cap(got)) // preserved
+			diffFatal(t, c.v[:n], got[:n], cmpopts.EquateEmpty())
+		})
+	}
+}
+
+func TestGrowExactAllocs(t *testing.T) {
+	for _, c := range []struct{ n1, n2, want int }{{10, 5, 1}, {10, 10, 1}, {10, 11, 2}, {10, 100, 2}} {
+		t.Run(fmt.Sprintf("n1=%v,n2=%v", c.n1, c.n2), func(t *testing.T) {
+			allocs := testing.AllocsPerRun(1000, func() {
+				buf := make([]byte, c.n1)
+				buf = bytepool.GrowExact(buf, c.n2)
+				if cap(buf) != c.n2 {
+					t.Fatal(buf)
+				}
+			})
+			diffFatal(t, float64(c.want), allocs) // make and maybe grow
+		})
+	}
+}
+
+func TestGrowKeep(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		v       []byte
+		min     int
+		wantCap int
+	}{
+		{nil, -1, 0},
+		{nil, 0, 0},
+		{nil, 1, 8},
+		{nil, 9, 16},
+
+		{[]byte{1}, -1, 1},
+		{[]byte{1}, 1, 1},
+		{[]byte{1}, 2, 8},
+		{[]byte{1}, 9, 16},
+
+		{[]byte{1, 2}, 2, 2},
+		{[]byte{1, 2}, 3, 8},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("v=%v,min=%v", c.v, c.min), func(t *testing.T) {
+			v := make([]byte, len(c.v)) // exact cap
+			copy(v, c.v)
+			got := bytepool.GrowKeep(v, c.min)
+			diffFatal(t, c.v, got, cmpopts.EquateEmpty())
+			if cap(got) != c.wantCap {
+				t.Fatal(cap(got), c.wantCap)
+			}
+		})
+	}
+}
+
+func TestGrowKeepAllocs(t *testing.T) {
+	v := make([]byte, 3, 10)
+	allocs := testing.AllocsPerRun(1000, func() {
+		bytepool.GrowKeep(v, 11)
+	})
+	diffFatal(t, 1.0, allocs)
+}
+
 func TestSized(t *testing.T) {
 	t.Parallel()
 
@@ -267,6 +457,203 @@ func TestSized(t *testing.T) {
 	})
 }
 
+func TestSizedKeep(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		v       []byte
+		size    int
+		want    []byte
+		wantCap int
+	}{
+		{nil, -1, nil, 0},
+		{nil, 0, nil, 0},
+		{nil, 3, []byte{}, 3},
+
+		{[]byte{1}, -1, []byte{}, 1},
+		{[]byte{1}, 0, []byte{}, 1},
+		{[]byte{1}, 1, []byte{1}, 1},
+		{[]byte{1}, 2, []byte{1}, 2},
+
+		{[]byte{1, 2, 3}, 2, []byte{1, 2}, 3},
+		{[]byte{1, 2, 3}, 9, []byte{1, 2, 3}, 9},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("v=%v,size=%v", c.v, c.size), func(t *testing.T) {
+			v := make([]byte, len(c.v)) // exact cap
+			copy(v, c.v)
+			got := bytepool.SizedKeep(v, c.size)
+			diffFatal(t, c.want, got, cmpopts.EquateEmpty())
+			diffFatal(t, c.wantCap, cap(got))
+		})
+	}
+}
+
+func TestShrink(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		len, cap, maxCap int
+		wantCap          int
+	}{
+		{0, 0, 0, 0},
+		{0, 10, 10, 10},
+		{0, 10, 9, 0},
+		{3, 10, 10, 10},
+		{3, 10, 4, 3},
+		{5, 10, 4, 5},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("len=%v,cap=%v,max=%v", c.len, c.cap, c.maxCap), func(t *testing.T) {
+			v := make([]byte, c.len, c.cap)
+			for i := range v {
+				v[i] = byte(i + 1)
+			}
+			got := bytepool.Shrink(v, c.maxCap)
+			diffFatal(t, v, got)
+			diffFatal(t, c.wantCap, cap(got))
+		})
+	}
+}
+
+func TestShrinkPooled(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(4, 64)
+
+	b := pool.GetGrown(64)
+	b.B = append(b.B, 1, 2, 3, 4, 5)
+
+	got := bytepool.ShrinkPooled(pool, b, 64)
+	if got != b {
+		t.Fatal("replaced")
+	}
+
+	got = bytepool.ShrinkPooled(pool, b, 16)
+	diffFatal(t, []byte{1, 2, 3, 4, 5}, got.B)
+	diffFatal(t, 8, cap(got.B))
+	got.Release()
+}
+
+func TestCopyFrom(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(4, 64)
+
+	src := []byte{1, 2, 3, 4, 5}
+	b := bytepool.CopyFrom(pool, src)
+	src[0] = 9
+	diffFatal(t, []byte{1, 2, 3, 4, 5}, b.B)
+	diffFatal(t, 8, cap(b.B))
+	b.Release()
+
+	b = bytepool.CopyFromString(pool, "abc")
+	diffFatal(t, "abc", string(b.B))
+	b.Release()
+}
+
+func TestShrinkToFit(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(4, 64)
+
+	b := pool.GetGrown(64)
+	b.B = append(b.B, 1, 2, 3, 4, 5)
+	b = bytepool.ShrinkToFit(pool, b)
+	diffFatal(t, []byte{1, 2, 3, 4, 5}, b.B)
+	diffFatal(t, 8, cap(b.B))
+
+	if bytepool.ShrinkToFit(pool, b) != b {
+		t.Fatal("replaced when fit")
+	}
+	b.Release()
+}
+
+func TestShrinkToFit_noGet(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucket(4, 64)
+
+	b := pool.GetGrown(8)
+	b.B = append(b.B, 1, 2, 3, 4, 5)
+	gets := pool.Stats().Gets
+	if bytepool.ShrinkToFit(pool, b) != b {
+		t.Fatal("replaced when fit")
+	}
+	diffFatal(t, gets, pool.Stats().Gets)
+	b.Release()
+}
+
+func TestGrowPooled(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{4, 16}, bytepool.BucketPoolOptions{MaxRetained: 4})
+
+	b := bytepool.GrowPooled(pool, nil, 3)
+	diffFatal(t, [2]int{0, 4}, [2]int{len(b.B), cap(b.B)})
+	b.B = append(b.B, 1, 2, 3)
+	if bytepool.GrowPooled(pool, b, 4) != b {
+		t.Fatal("replaced within cap")
+	}
+
+	b = bytepool.GrowPooled(pool, b, 10)
+	diffFatal(t, []byte{1, 2, 3}, b.B)
+	diffFatal(t, 16, cap(b.B))
+	diffFatal(t, 1, pool.Stats().Pooled) // the outgrown 4.
+	b.Release()
+}
+
+func TestGrowPooled_doubles(t *testing.T) {
+	t.Parallel()
+
+	for _, pool := range []bytepool.SizedPooler{
+		bytepool.NewBucket(4, 16), // past the largest bucket.
+		bytepool.NewSync(),
+	} {
+		var b *bytepool.Bytes
+		var grows int
+		for i := range 1000 {
+			prev := b
+			b = bytepool.GrowPooled(pool, b, i+1)
+			if b != prev {
+				grows++
+			}
+			b.B = append(b.B, byte(i))
+		}
+		diffFatal(t, 1000, len(b.B))
+		if grows > 12 {
+			t.Fatal("grows", grows)
+		}
+		b.Release()
+	}
+}
+
+func TestAppendPooled(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{4, 8, 16}, bytepool.BucketPoolOptions{MaxRetained: 4})
+
+	b := bytepool.AppendPooled(pool, nil, 1, 2, 3)
+	diffFatal(t, 4, cap(b.B))
+	same := bytepool.AppendPooled(pool, b, 4)
+	if same != b {
+		t.Fatal("replaced within cap")
+	}
+	b = bytepool.AppendStringPooled(pool, b, "\x05\x06")
+	diffFatal(t, []byte{1, 2, 3, 4, 5, 6}, b.B)
+	diffFatal(t, 8, cap(b.B))
+	diffFatal(t, 1, pool.Stats().Pooled) // the outgrown 4.
+
+	b = bytepool.AppendPooled(pool, b, make([]byte, 20)...) // over.
+	diffFatal(t, 26, len(b.B))
+	b = bytepool.AppendPooled(pool, b, 7)
+	diffFatal(t, 52, cap(b.B)) // doubled.
+	b.Release()
+}
+
 func BenchmarkSizedPooler(b *testing.B) {
 	run := func(b *testing.B, pool bytepool.SizedPooler, doRelease bool) {
 		b.RunParallel(func(p *testing.PB) {
@@ -307,3 +694,19 @@ func diffFatal(t testing.TB, want, got any, opts ...cmp.Option) {
 		t.Fatalf("(-want +got):\n%v", d)
 	}
 }
+
+func TestBytes_UnsafeString(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(2, 8)
+	b := pool.GetGrown(3)
+	b.B = append(b.B, "abc"...)
+	s := b.UnsafeString()
+	diffFatal(t, "abc", s)
+	if unsafe.StringData(s) != unsafe.SliceData(b.B) {
+		t.Fatal("copied")
+	}
+	b.Release()
+
+	diffFatal(t, "", new(bytepool.Bytes).UnsafeString())
+}
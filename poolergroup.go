@@ -0,0 +1,54 @@
+package bytepool
+
+import (
+	"sync"
+)
+
+// BucketPoolers by key, such as content type or endpoint, over one BucketPool.
+// Each key adapts its default size to its own traffic.
+type PoolerGroup struct {
+	pool    *BucketPool
+	options BucketPoolerOptions
+
+	poolers sync.Map // key to *BucketPooler.
+	mu      sync.Mutex
+}
+
+// o applies to every BucketPooler in the group.
+func (p *BucketPool) PoolerGroup(o BucketPoolerOptions) *PoolerGroup {
+	return &PoolerGroup{pool: p, options: o}
+}
+
+// BucketPooler for key, created on first use. Concurrent first uses get the same BucketPooler.
+func (g *PoolerGroup) Pooler(key string) *BucketPooler {
+	if v, ok := g.poolers.Load(key); ok {
+		return v.(*BucketPooler)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if v, ok := g.poolers.Load(key); ok {
+		return v.(*BucketPooler)
+	}
+	pooler := g.pool.Pooler(g.options)
+	g.poolers.Store(key, pooler)
+	return pooler
+}
+
+// Calls fn for each created BucketPooler until fn returns false, in no particular order.
+func (g *PoolerGroup) Range(fn func(key string, p *BucketPooler) bool) {
+	g.poolers.Range(func(k, v any) bool {
+		return fn(k.(string), v.(*BucketPooler))
+	})
+}
+
+// Stats of each created BucketPooler by key.
+func (g *PoolerGroup) Stats() map[string]BucketPoolerStats {
+	stats := make(map[string]BucketPoolerStats)
+	g.Range(func(key string, p *BucketPooler) bool {
+		stats[key] = p.Stats()
+		return true
+	})
+	return stats
+}
@@ -0,0 +1,61 @@
+package bytepool_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestPoolerGroup(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucket(8, 1024)
+	group := pool.PoolerGroup(bytepool.BucketPoolerOptions{ChooseInc: 10})
+
+	for range 100 {
+		b := group.Pooler("small").Get()
+		b.B = append(b.B, make([]byte, 8)...)
+		b.Release()
+
+		b = group.Pooler("large").Get()
+		b.B = append(b.B, make([]byte, 1000)...)
+		b.Release()
+	}
+
+	stats := group.Stats()
+	diffFatal(t, 8, stats["small"].DefaultSize)
+	diffFatal(t, 1024, stats["large"].DefaultSize)
+	diffFatal(t, 2, len(stats))
+}
+
+func TestPoolerGroup_concurrent(t *testing.T) {
+	t.Parallel()
+
+	group := bytepool.NewBucket(8, 1024).PoolerGroup(bytepool.BucketPoolerOptions{})
+
+	var wait sync.WaitGroup
+	poolers := make([]*bytepool.BucketPooler, 10)
+	for i := range poolers {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			poolers[i] = group.Pooler("key")
+		}()
+	}
+	wait.Wait()
+
+	for _, p := range poolers {
+		if p != poolers[0] {
+			t.Fatal("different poolers")
+		}
+	}
+
+	var n int
+	group.Range(func(key string, p *bytepool.BucketPooler) bool {
+		n++
+		return true
+	})
+	diffFatal(t, 1, n)
+}
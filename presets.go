@@ -0,0 +1,37 @@
+package bytepool
+
+// Presets bundle sizes and pooler options for common workloads. The numbers are starting
+// points from BenchmarkPresets rather than tuned for any service; measure with real sizes and
+// use NewBucketOptions and Pooler directly to tune further.
+
+// Messages up to 16 KiB, such as RPC and queue payloads.
+func NewForSmallMessages() *BucketPooler {
+	pool := NewBucketFull(ExpoSizes(64, 16<<10, 17))
+	return pool.Pooler(BucketPoolerOptions{
+		ChooseInc: 1000,
+		Decay:     0.5,
+		BinChecks: 4,
+	})
+}
+
+// Bodies up to 4 MiB, such as HTTP requests and responses.
+func NewForHTTPBodies() *BucketPooler {
+	pool := NewBucketFull(ExpoSizes(512, 4<<20, 27))
+	return pool.Pooler(BucketPoolerOptions{
+		ChooseInc: 500,
+		Decay:     0.5,
+		BinChecks: 3,
+	})
+}
+
+// Blobs up to 64 MiB, such as files and object storage parts.
+// Fewer puts are seen so choosing is quicker and decay slower, and lookahead is short
+// to avoid handing out much larger buffers than needed.
+func NewForLargeBlobs() *BucketPooler {
+	pool := NewBucketFull(Pow2Sizes(64<<10, 64<<20))
+	return pool.Pooler(BucketPoolerOptions{
+		ChooseInc: 100,
+		Decay:     0.75,
+		BinChecks: 2,
+	})
+}
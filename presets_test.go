@@ -0,0 +1,76 @@
+package bytepool_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestPresets(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		pooler  *bytepool.BucketPooler
+		size    int
+		wantMin int
+	}{
+		{"small", bytepool.NewForSmallMessages(), 1000, 64},
+		{"http", bytepool.NewForHTTPBodies(), 100_000, 512},
+		{"blobs", bytepool.NewForLargeBlobs(), 5 << 20, 64 << 10},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			diffFatal(t, c.wantMin, c.pooler.Stats().DefaultSize)
+
+			for range 100 {
+				b := c.pooler.Get()
+				fillBytes(b, c.size)
+				b.Release()
+			}
+			def := c.pooler.Stats().DefaultSize
+			if def < c.size || def > c.size*2 {
+				t.Fatal(def)
+			}
+		})
+	}
+}
+
+// Sizes log-uniform over each preset's range. Reports capacity handed out per byte used,
+// to compare against tuned options.
+func BenchmarkPresets(b *testing.B) {
+	cases := []struct {
+		name     string
+		pooler   func() *bytepool.BucketPooler
+		min, max int
+	}{
+		{"small", bytepool.NewForSmallMessages, 64, 16 << 10},
+		{"http", bytepool.NewForHTTPBodies, 512, 4 << 20},
+		{"blobs", bytepool.NewForLargeBlobs, 64 << 10, 64 << 20},
+	}
+	for _, c := range cases {
+		b.Run(c.name, func(b *testing.B) {
+			pooler := c.pooler()
+			r := rand.New(rand.NewPCG(1, 2))
+			sizes := make([]int, 1024)
+			for i := range sizes {
+				sizes[i] = int(float64(c.min) * math.Pow(float64(c.max)/float64(c.min), r.Float64()))
+			}
+
+			var used, handed float64
+			b.ResetTimer()
+			for i := range b.N {
+				size := sizes[i%len(sizes)]
+				bs := bytepool.GrowPooled(pooler, pooler.Get(), size) // regrows through the buckets.
+				bs.B = bs.B[:size]
+				used += float64(size)
+				handed += float64(cap(bs.B))
+				bs.Release()
+			}
+			b.ReportMetric(handed/used, "cap/len")
+		})
+	}
+}
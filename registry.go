@@ -0,0 +1,32 @@
+package bytepool
+
+import (
+	"maps"
+	"sync"
+)
+
+var registry = struct {
+	mu    sync.Mutex
+	pools map[string]MetricsReader
+}{pools: make(map[string]MetricsReader)}
+
+// Adds r under name for generic tooling such as debug handlers, replacing any previous.
+// r can optionally implement ResetStats() and Drain() int.
+func Register(name string, r MetricsReader) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.pools[name] = r
+}
+
+func Unregister(name string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	delete(registry.pools, name)
+}
+
+// Copy of the registered pools by name.
+func Registered() map[string]MetricsReader {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	return maps.Clone(registry.pools)
+}
@@ -0,0 +1,29 @@
+package bytepool_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(2, 8)
+	bytepool.Register("registry-test", pool)
+
+	regs := bytepool.Registered()
+	if regs["registry-test"] != pool {
+		t.Fatal(regs)
+	}
+	delete(regs, "registry-test") // copy
+
+	if _, ok := bytepool.Registered()["registry-test"]; !ok {
+		t.Fatal("missing")
+	}
+
+	bytepool.Unregister("registry-test")
+	if _, ok := bytepool.Registered()["registry-test"]; ok {
+		t.Fatal("still registered")
+	}
+}
@@ -0,0 +1,94 @@
+package bytepool
+
+import "runtime"
+
+// Capacity claimed by Reserve, redeemed by Get or returned by Cancel.
+type Reservation struct {
+	pool     *BucketPool
+	sp       *sizedPool // nil for overs.
+	c        int
+	b        *Bytes // retained Bytes taken by Reserve, nil to allocate on Get.
+	reserved int    // MemoryLimit bytes claimed for an allocation.
+	done     bool
+}
+
+// Claims capacity for a later Get of c: a retained Bytes when one is available, otherwise
+// MemoryLimit budget for the allocation. Returns ErrMemoryLimit when the allocation would go
+// over a Hard limit, so callers can refuse work before starting it. Reservations count in
+// Memory until redeemed or cancelled. One dropped without either is cancelled once collected,
+// holding its capacity until then.
+func (p *BucketPool) Reserve(c int) (*Reservation, error) {
+	r := &Reservation{pool: p, c: c}
+	size, mem := c, p.mem
+	if _, sp := p.findPool(c); sp != nil {
+		if r.b = sp.getNoAlloc(p); r.b != nil {
+			runtime.SetFinalizer(r, (*Reservation).Cancel)
+			return r, nil
+		}
+		r.sp, size, mem = sp, sp.size, sp.mem
+	}
+	if mem != nil {
+		if !mem.reserve(size) {
+			return nil, ErrMemoryLimit
+		}
+		r.reserved = size
+		runtime.SetFinalizer(r, (*Reservation).Cancel)
+	}
+	return r, nil
+}
+
+// Bytes with zero length and minimum capacity c, as GetGrown.
+// Panics if already redeemed or cancelled, or with NoPanics gets as GetGrown.
+func (r *Reservation) Get() *Bytes {
+	if r.done {
+		if !r.pool.misused("Reservation already used") {
+			panic("Reservation already used")
+		}
+		return r.pool.GetGrown(r.c)
+	}
+	r.done = true
+	runtime.SetFinalizer(r, nil)
+	if r.b != nil {
+		return r.b
+	}
+	var b *Bytes
+	mem := r.pool.mem
+	if r.sp != nil {
+		r.sp.limit.admit()
+		b = r.sp.allocateUncounted(r.pool)
+		mem = r.sp.mem
+	} else {
+		r.pool.over(r.c, false)
+		r.pool.limit.admit()
+		b = r.pool.makeOverUncounted(r.c)
+	}
+	if r.reserved > 0 {
+		b.counted = memCount{mem, r.reserved} // the reserved budget, never released in between.
+	}
+	return b
+}
+
+// Returns the claimed capacity. Does nothing after Get or Cancel.
+func (r *Reservation) Cancel() {
+	if r.done {
+		return
+	}
+	r.done = true
+	runtime.SetFinalizer(r, nil)
+	if r.b != nil {
+		r.b.Release()
+		return
+	}
+	r.release()
+}
+
+func (r *Reservation) release() {
+	if r.reserved == 0 {
+		return
+	}
+	if r.sp != nil {
+		r.sp.mem.add(-r.reserved)
+	} else {
+		r.pool.mem.add(-r.reserved)
+	}
+}
@@ -0,0 +1,82 @@
+package bytepool_test
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_Reserve(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{100}, bytepool.BucketPoolOptions{
+		MaxRetained: 4,
+		MemoryLimit: bytepool.MemoryLimit{Hard: 250},
+	})
+
+	r1, err := pool.Reserve(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := pool.Reserve(50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Reserve(100); !errors.Is(err, bytepool.ErrMemoryLimit) {
+		t.Fatal(err)
+	}
+	diffFatal(t, int64(200), pool.Stats().Memory)
+
+	b := r1.Get()
+	diffFatal(t, [2]int{0, 100}, [2]int{len(b.B), cap(b.B)})
+	r2.Cancel()
+	r2.Cancel()
+	diffFatal(t, int64(100), pool.Stats().Memory)
+
+	b.Release()
+	r3, err := pool.Reserve(10) // takes the retained one.
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, 0, pool.Stats().Pooled)
+	if r3.Get() != b {
+		t.Fatal("not the retained Bytes")
+	}
+	diffFatal(t, int64(100), pool.Stats().Memory)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic")
+		}
+	}()
+	r3.Get()
+}
+
+func TestBucket_ReserveDropped(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{100}, bytepool.BucketPoolOptions{
+		MaxRetained: 4,
+		MemoryLimit: bytepool.MemoryLimit{Hard: 250},
+	})
+
+	func() {
+		if _, err := pool.Reserve(100); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	diffFatal(t, int64(100), pool.Stats().Memory)
+
+	for i := 0; pool.Stats().Memory != 0; i++ {
+		if i == 100 {
+			t.Fatal("dropped Reservation not cancelled")
+		}
+		runtime.GC()
+		time.Sleep(time.Millisecond) // finalizers run on their own goroutine.
+	}
+}
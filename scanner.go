@@ -0,0 +1,201 @@
+package bytepool
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// Like bufio.Scanner, though the buffer and tokens are pooled Bytes. Each token is copied into
+// its own Bytes, valid until released, rather than a view overwritten by the next Scan.
+type Scanner struct {
+	r       io.Reader
+	pool    SizedPooler
+	split   bufio.SplitFunc
+	maxSize int
+
+	buf        *Bytes // data is B[start:end], len(B) is the buffer size.
+	start, end int
+	token      *Bytes
+	err        error
+	done       bool
+	empties    int // consecutive empty tokens without advancing.
+}
+
+// Splits by lines, as bufio.ScanLines.
+func NewScanner(r io.Reader, p SizedPooler) *Scanner {
+	return &Scanner{
+		r:       r,
+		pool:    p,
+		split:   bufio.ScanLines,
+		maxSize: bufio.MaxScanTokenSize,
+	}
+}
+
+// Sets the split function. Panics if called after Scan.
+func (s *Scanner) Split(split bufio.SplitFunc) {
+	if s.buf != nil || s.done {
+		panic("Split called after Scan")
+	}
+	s.split = split
+}
+
+// Sets the maximum buffer size, and so the maximum token size. Defaults to bufio.MaxScanTokenSize.
+// Panics if called after Scan.
+func (s *Scanner) Buffer(maxSize int) {
+	if s.buf != nil || s.done {
+		panic("Buffer called after Scan")
+	}
+	s.maxSize = maxSize
+}
+
+// Advances to the next token, which is then available through Token.
+// Returns false at the end of input or on error, after which the buffer is released.
+func (s *Scanner) Scan() bool {
+	s.token.Release() // not taken.
+	s.token = nil
+	if s.done {
+		return false
+	}
+
+	for {
+		if s.end > s.start || s.err != nil {
+			var data []byte
+			if s.buf != nil {
+				data = s.buf.B[s.start:s.end]
+			}
+			adv, tok, err := s.split(data, s.err != nil)
+			if err != nil {
+				if errors.Is(err, bufio.ErrFinalToken) {
+					s.setToken(tok)
+					s.finish(nil)
+					return tok != nil
+				}
+				s.finish(err)
+				return false
+			}
+			if adv < 0 {
+				s.finish(bufio.ErrNegativeAdvance)
+				return false
+			}
+			if adv > len(data) {
+				s.finish(bufio.ErrAdvanceTooFar)
+				return false
+			}
+			s.start += adv
+			if tok != nil {
+				if adv > 0 {
+					s.empties = 0
+				} else if s.empties++; s.empties > 100 {
+					panic("too many empty tokens without progressing")
+				}
+				s.setToken(tok)
+				return true
+			}
+		}
+		if s.err != nil {
+			s.finish(s.err)
+			return false
+		}
+		if !s.fill() {
+			return false
+		}
+	}
+}
+
+// Token from the last Scan, handed to the caller who must Release it.
+// Returns nil when already taken or if Scan returned false.
+// Tokens not taken are released by the next Scan.
+func (s *Scanner) Token() *Bytes {
+	t := s.token
+	s.token = nil
+	return t
+}
+
+// First non EOF error.
+func (s *Scanner) Err() error {
+	if errors.Is(s.err, io.EOF) {
+		return nil
+	}
+	return s.err
+}
+
+// Releases the buffer and any token not taken, for stopping before Scan returns false.
+func (s *Scanner) Release() {
+	s.token.Release()
+	s.token = nil
+	s.finish(s.err)
+}
+
+func (s *Scanner) setToken(tok []byte) {
+	if tok == nil {
+		return
+	}
+	s.token = s.pool.GetFilled(len(tok))
+	copy(s.token.B, tok)
+}
+
+func (s *Scanner) finish(err error) {
+	s.err = err
+	s.done = true
+	s.buf.Release()
+	s.buf = nil
+	s.start, s.end = 0, 0
+}
+
+// reads more data, growing or shifting the buffer. Returns false when finished.
+func (s *Scanner) fill() bool {
+	if s.buf != nil && s.start > 0 && (s.end == len(s.buf.B) || s.start > len(s.buf.B)/2) {
+		copy(s.buf.B, s.buf.B[s.start:s.end])
+		s.end -= s.start
+		s.start = 0
+	}
+	if s.buf == nil || s.end == len(s.buf.B) {
+		size, ok := growScanBuffer(bytesLen(s.buf), s.maxSize)
+		if !ok {
+			s.finish(bufio.ErrTooLong)
+			return false
+		}
+		nb := s.pool.GetFilled(size)
+		if s.buf != nil {
+			copy(nb.B, s.buf.B[s.start:s.end])
+			s.end -= s.start
+			s.start = 0
+			s.buf.Release()
+		}
+		s.buf = nb
+	}
+
+	var n int
+	n, s.err = readScanBuffer(s.r, s.buf.B[s.end:])
+	s.end += n
+	return true
+}
+
+var errReadCount = errors.New("bytepool: Read returned impossible count")
+
+// Size of a scan buffer grown from size, 4096 for the first. False when size is maxSize.
+func growScanBuffer(size, maxSize int) (int, bool) {
+	if size == 0 {
+		return min(4096, maxSize), true
+	}
+	if size >= maxSize {
+		return 0, false
+	}
+	return min(2*size, maxSize), true
+}
+
+// Reads into buf, retrying empty reads as bufio.Scanner does. The error is from Read,
+// or io.ErrNoProgress after repeated empty reads.
+func readScanBuffer(r io.Reader, buf []byte) (int, error) {
+	for range 100 {
+		n, err := r.Read(buf)
+		if n < 0 || n > len(buf) {
+			return 0, errReadCount
+		}
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+	return 0, io.ErrNoProgress
+}
@@ -0,0 +1,98 @@
+package bytepool_test
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestScanner(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("x", 10000)
+	input := "a\nbb\r\n\n" + long + "\nlast"
+
+	pool := bytepool.NewBucket(8, 1<<16)
+	s := bytepool.NewScanner(iotest.OneByteReader(strings.NewReader(input)), pool)
+
+	var tokens []*bytepool.Bytes
+	for s.Scan() {
+		tokens = append(tokens, s.Token())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, tok := range tokens { // all still valid
+		got = append(got, string(tok.B))
+		tok.Release()
+	}
+	diffFatal(t, []string{"a", "bb", "", long, "last"}, got)
+
+	st := pool.Stats()
+	diffFatal(t, st.Gets, st.Puts) // buffers and tokens returned
+}
+
+func TestScanner_words(t *testing.T) {
+	t.Parallel()
+
+	s := bytepool.NewScanner(strings.NewReader(" one two  three "), bytepool.NewSync())
+	s.Split(bufio.ScanWords)
+
+	var got []string
+	for s.Scan() {
+		got = append(got, string(s.Token().B)) // not released, fine
+	}
+	diffFatal(t, []string{"one", "two", "three"}, got)
+	if s.Token() != nil {
+		t.Fatal("token after end")
+	}
+}
+
+func TestScanner_tooLong(t *testing.T) {
+	t.Parallel()
+
+	s := bytepool.NewScanner(strings.NewReader(strings.Repeat("x", 100)+"\n"), bytepool.NewSync())
+	s.Buffer(10)
+	if s.Scan() {
+		t.Fatal("expected no token")
+	}
+	if !errors.Is(s.Err(), bufio.ErrTooLong) {
+		t.Fatal(s.Err())
+	}
+}
+
+func TestScanner_readErr(t *testing.T) {
+	t.Parallel()
+
+	errRead := errors.New("read")
+	s := bytepool.NewScanner(iotest.ErrReader(errRead), bytepool.NewSync())
+	if s.Scan() {
+		t.Fatal("expected no token")
+	}
+	if !errors.Is(s.Err(), errRead) {
+		t.Fatal(s.Err())
+	}
+}
+
+func TestScanner_release(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(8, 1<<16)
+	s := bytepool.NewScanner(strings.NewReader("a\nb\nc\n"), pool)
+	if !s.Scan() {
+		t.Fatal(s.Err())
+	}
+	s.Release()
+	if s.Scan() {
+		t.Fatal("scan after release")
+	}
+
+	st := pool.Stats()
+	diffFatal(t, st.Gets, st.Puts)
+}
@@ -0,0 +1,129 @@
+package bytepool
+
+import "unsafe"
+
+type SecureOptions struct {
+	MaxRetained int // Bytes each size retains, further releases are freed. Defaults to 16.
+}
+
+// For sensitive data such as keys. Backing memory is locked against swapping where permitted
+// (Linux, within RLIMIT_MEMLOCK), every release is zeroed, and Bytes never migrate to other
+// pools: their Put returns them here and their Adopt ignores them.
+// Retained Bytes are not subject to GC drops. Each array is rounded up to a page, so prefer
+// few sizes. Bytes whose B was resliced or replaced are zeroed and returned to their
+// original array.
+type SecurePool struct {
+	pool    *BucketPool
+	alloc   *lockedAllocator
+	maxSize int
+}
+
+type SecurePoolStats struct {
+	BucketPoolStats
+	LockFailures uint64 // allocations not locked, such as when over RLIMIT_MEMLOCK.
+}
+
+// sizes as NewBucketFull.
+func NewSecure(sizes []int, o SecureOptions) *SecurePool {
+	if o.MaxRetained <= 0 {
+		o.MaxRetained = 16
+	}
+	alloc := newLockedAllocator()
+	pool := NewBucketOptions(sizes, BucketPoolOptions{
+		Allocator:   alloc,
+		MaxRetained: o.MaxRetained,
+	})
+	return &SecurePool{
+		pool:    pool,
+		alloc:   alloc,
+		maxSize: pool.pools[len(pool.pools)-1].size,
+	}
+}
+
+// Bytes with zero length and minimum capacity c, all zero.
+func (s *SecurePool) GetGrown(c int) *Bytes {
+	var b *Bytes
+	if c > s.maxSize {
+		s.pool.over(c, false)
+		b = allocSizedBytes(s.alloc, c, s, nil) // locked too, unlike BucketPool overs.
+	} else {
+		b = s.pool.GetGrown(c)
+	}
+	b.pool = s
+	return b
+}
+
+// Bytes with length, all zero.
+func (s *SecurePool) GetFilled(length int) *Bytes {
+	b := s.GetGrown(length)
+	b.B = b.B[:length]
+	return b
+}
+
+// As BucketPool.Sizes.
+func (s *SecurePool) Sizes() SizeSet {
+	return s.pool.Sizes()
+}
+
+func (s *SecurePool) bucketMax() int {
+	return s.maxSize
+}
+
+func (s *SecurePool) bucketFit(c int) int {
+	return s.pool.bucketFit(c)
+}
+
+// As BucketPool.Inspect.
+func (s *SecurePool) Inspect() []BucketContents {
+	return s.pool.Inspect()
+}
+
+func (s *SecurePool) Stats() SecurePoolStats {
+	return SecurePoolStats{
+		BucketPoolStats: s.pool.Stats(),
+		LockFailures:    s.alloc.lockFailures.Load(),
+	}
+}
+
+func (s *SecurePool) put(b *Bytes) {
+	if b == nil {
+		return
+	}
+	clear(b.B[:cap(b.B)])
+
+	region, ok := s.alloc.region(b.base)
+	if !ok {
+		b.B = nil
+		b.pool = nil
+		return
+	}
+	if unsafe.SliceData(b.B[:cap(b.B)]) != b.base || cap(b.B) != cap(region) {
+		clear(region[:cap(region)]) // resliced or replaced B, back to the original.
+		b.B = region
+	}
+	if cap(b.B) > s.maxSize {
+		s.pool.over(cap(b.B), true)
+		freeAllocated(s.alloc, b, cap(region))
+		return
+	}
+	s.pool.put(b)
+}
+
+// Makes p the origin of b, unless b must stay in its SecurePool, PinnedPool, SharedPool or lease.
+// Panics on a view from an Arena, Tokenizer or SharedPool, or with p's NoPanics leaves it be.
+func adopt(b *Bytes, p poolPutter) {
+	if b.view {
+		if m, ok := p.(misuser); !ok || !m.misused("Put or Adopt of view Bytes") {
+			panic("Put or Adopt of view Bytes")
+		}
+		return
+	}
+	switch b.pool.(type) {
+	case *SecurePool, *PinnedPool, *SharedPool, *lease, *tokenChunk:
+	default:
+		if b.pool != p {
+			countMem(b, nil, 0) // counted again if released into a budgeted pool.
+		}
+		b.pool = p
+	}
+}
@@ -0,0 +1,94 @@
+//go:build linux
+
+package bytepool
+
+import (
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+// mmap regions locked into memory, falling back to unlocked when mlock is not permitted, and to
+// the Go heap when mmap fails.
+type lockedAllocator struct {
+	mu           sync.Mutex
+	regions      map[uintptr]lockedRegion // keyed by region start.
+	lockFailures atomic.Uint64
+}
+
+type lockedRegion struct {
+	b    []byte
+	heap bool // mmap failed.
+}
+
+func newLockedAllocator() *lockedAllocator {
+	return &lockedAllocator{regions: make(map[uintptr]lockedRegion)}
+}
+
+func (a *lockedAllocator) Alloc(c int) []byte {
+	if c <= 0 {
+		return nil
+	}
+	r := lockedRegion{}
+	var err error
+	r.b, err = syscall.Mmap(-1, 0, c, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		r.b = make([]byte, c)
+		r.heap = true
+		a.lockFailures.Add(1)
+	} else if err := syscall.Mlock(r.b); err != nil {
+		a.lockFailures.Add(1)
+	}
+
+	a.mu.Lock()
+	a.regions[regionKey(r.b)] = r
+	a.mu.Unlock()
+
+	return r.b[:0:c]
+}
+
+func (a *lockedAllocator) Free(b []byte) {
+	if cap(b) == 0 {
+		return
+	}
+	k := regionKey(b)
+
+	a.mu.Lock()
+	r, ok := a.regions[k]
+	delete(a.regions, k)
+	a.mu.Unlock()
+
+	if ok {
+		clear(r.b)
+		if !r.heap {
+			_ = syscall.Munlock(r.b)
+			_ = syscall.Munmap(r.b)
+		}
+	}
+}
+
+func (a *lockedAllocator) owns(b []byte) bool {
+	if cap(b) == 0 {
+		return false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, ok := a.regions[regionKey(b)]
+	return ok
+}
+
+// The whole array starting at base, false if not allocated here or already freed.
+func (a *lockedAllocator) region(base *byte) ([]byte, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	r, ok := a.regions[uintptr(unsafe.Pointer(base))]
+	return r.b[:0:len(r.b)], ok
+}
+
+// Whether b's array is on the Go heap, so must be pinned to be passed outside Go.
+func (a *lockedAllocator) heap(b []byte) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.regions[regionKey(b)].heap
+}
@@ -0,0 +1,73 @@
+//go:build !linux
+
+package bytepool
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Go heap, as memory locking is only supported on Linux. Each Alloc counts as a lock failure.
+type lockedAllocator struct {
+	mu           sync.Mutex
+	arrays       map[*byte]int // to cap.
+	lockFailures atomic.Uint64
+}
+
+func newLockedAllocator() *lockedAllocator {
+	return &lockedAllocator{arrays: make(map[*byte]int)}
+}
+
+func (a *lockedAllocator) Alloc(c int) []byte {
+	if c <= 0 {
+		return nil
+	}
+	a.lockFailures.Add(1)
+	b := make([]byte, 0, c)
+
+	a.mu.Lock()
+	a.arrays[unsafe.SliceData(b)] = c
+	a.mu.Unlock()
+	return b
+}
+
+func (a *lockedAllocator) Free(b []byte) {
+	if cap(b) == 0 {
+		return
+	}
+	a.mu.Lock()
+	_, ok := a.arrays[unsafe.SliceData(b)]
+	delete(a.arrays, unsafe.SliceData(b))
+	a.mu.Unlock()
+
+	if ok {
+		clear(b[:cap(b)])
+	}
+}
+
+func (a *lockedAllocator) owns(b []byte) bool {
+	if cap(b) == 0 {
+		return false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, ok := a.arrays[unsafe.SliceData(b)]
+	return ok
+}
+
+// The whole array starting at base, false if not allocated here or already freed.
+func (a *lockedAllocator) region(base *byte) ([]byte, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	c, ok := a.arrays[base]
+	if !ok {
+		return nil, false
+	}
+	return unsafe.Slice(base, c)[:0], true
+}
+
+// Whether b's array is on the Go heap, so must be pinned to be passed outside Go.
+func (a *lockedAllocator) heap([]byte) bool {
+	return true
+}
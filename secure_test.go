@@ -0,0 +1,96 @@
+package bytepool_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestSecure(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewSecure([]int{32, 4096}, bytepool.SecureOptions{})
+
+	b := pool.GetFilled(20)
+	diffFatal(t, 32, cap(b.B))
+	diffFatal(t, make([]byte, 20), b.B)
+	for i := range b.B {
+		b.B[i] = 0xff
+	}
+	data := unsafe.SliceData(b.B)
+	b.Release()
+
+	b = pool.GetFilled(32) // retained by the free list, not dropped by GC.
+	if unsafe.SliceData(b.B) != data {
+		t.Fatal("not reused")
+	}
+	diffFatal(t, make([]byte, 32), b.B) // zeroed on release
+	b.Release()
+
+	over := pool.GetGrown(5000)
+	if cap(over.B) < 5000 {
+		t.Fatal(cap(over.B))
+	}
+	over.B = append(over.B, 1, 2, 3)
+	over.Release()
+
+	s := pool.Stats()
+	diffFatal(t, uint64(2), s.Overs)
+}
+
+func TestSecure_noMigration(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	secure := bytepool.NewSecure([]int{32}, bytepool.SecureOptions{})
+	bucket := bytepool.NewBucket(8, 64)
+	others := []bytepool.SizedPool{
+		bytepool.NewSync(),
+		bytepool.NewDynamic(),
+		bucket,
+	}
+	for _, other := range others {
+		b := secure.GetGrown(32)
+		other.Adopt(b)
+		b.Release()
+
+		b = secure.GetGrown(32)
+		b.B = append(b.B, 1)
+		other.Put(b)
+	}
+
+	s := secure.Stats()
+	diffFatal(t, uint64(6), s.Gets)
+	diffFatal(t, uint64(6), s.Puts)
+	diffFatal(t, uint64(0), bucket.Stats().Puts)
+}
+
+func TestSecure_movedB(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewSecure([]int{32}, bytepool.SecureOptions{})
+
+	for _, move := range []func([]byte) []byte{
+		func(b []byte) []byte { return b[8:] },
+		func(b []byte) []byte { return b[:4:4] },
+		func([]byte) []byte { return make([]byte, 5) },
+		func([]byte) []byte { return nil },
+	} {
+		b := pool.GetFilled(32)
+		for i := range b.B {
+			b.B[i] = 0xff
+		}
+		data := unsafe.SliceData(b.B)
+		b.B = move(b.B)
+		b.Release()
+
+		b = pool.GetFilled(32) // the original array, zeroed.
+		if unsafe.SliceData(b.B) != data {
+			t.Fatal("not reused")
+		}
+		diffFatal(t, make([]byte, 32), b.B)
+		b.Release()
+	}
+}
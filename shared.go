@@ -0,0 +1,286 @@
+package bytepool
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+var ErrSharedUnsupported = errors.New("bytepool: shared pools are only supported on Linux")
+
+type SharedOptions struct {
+	SlotSize int // capacity of each Bytes. Required.
+	Slots    int // Bytes in the segment. Required.
+}
+
+// Experimental. Bytes of one size in a shared memory segment, such as a file under /dev/shm,
+// so processes on a host can share one set of large buffers rather than each holding its own.
+// Every process opening the same path with the same options shares a free list.
+//
+// A slot is owned by the process that got it until released. Slots of exited processes are
+// only freed by Reclaim. Gets over SlotSize or while the segment is empty are allocated
+// directly and dropped on release. Bytes never migrate to other pools, as SecurePool.
+// Memory written by one process is visible to another only after release and get, so do not
+// share a Bytes itself between processes.
+type SharedPool struct {
+	seg      []byte // header, slot metas, then slot data.
+	slotSize int
+	slots    int
+	dataOff  int     // of slot 0 in seg.
+	data     uintptr // address of slot 0.
+	pid      uint64
+
+	mu     sync.RWMutex // read held while using seg, Close waits for it to unmap.
+	closed bool
+
+	gets  counter
+	hits  counter
+	overs counter
+}
+
+type SharedPoolStats struct {
+	SlotSize int
+	Slots    int
+	Free     int    // across all processes.
+	Gets     uint64 // this process only, as are the other counters.
+	Hits     uint64
+	Overs    uint64 // over SlotSize or while the segment was empty.
+}
+
+// Segment layout, in host byte order as only shared on one host.
+const (
+	sharedMagic       = 0x62797465706f6f31 // "bytepoo1"
+	sharedMagicOff    = 0
+	sharedSlotSizeOff = 8
+	sharedSlotsOff    = 16
+	sharedHeadOff     = 24 // generation<<32 | slot+1 of the first free slot, 0 when empty.
+	sharedFreeOff     = 32
+	sharedHeaderLen   = 64
+
+	sharedMetaLen   = 16 // next free slot+1, then owning pid (0 when free).
+	sharedDataAlign = 4096
+)
+
+// Opens or creates the segment at path, such as "/dev/shm/myapp-buffers".
+// Opening an existing segment with different options errors. Close when done.
+func OpenShared(path string, o SharedOptions) (*SharedPool, error) {
+	if o.SlotSize < 1 || o.Slots < 1 || int64(o.Slots) >= 1<<32-1 {
+		panic("invalid SharedOptions")
+	}
+	dataOff := sharedDataOffset(o.Slots)
+	size := dataOff + o.SlotSize*o.Slots
+
+	p := &SharedPool{slotSize: o.SlotSize, slots: o.Slots, pid: uint64(os.Getpid())}
+	seg, err := mapShared(path, size, func(seg []byte, created bool) error {
+		p.seg = seg
+		if created {
+			p.init()
+			return nil
+		}
+		return p.validate(size)
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.seg = seg
+	p.dataOff = dataOff
+	p.data = uintptr(unsafe.Pointer(&seg[dataOff]))
+	return p, nil
+}
+
+func sharedDataOffset(slots int) int {
+	off := sharedHeaderLen + sharedMetaLen*slots
+	return (off + sharedDataAlign - 1) &^ (sharedDataAlign - 1)
+}
+
+func (p *SharedPool) word(off int) *atomic.Uint64 {
+	return (*atomic.Uint64)(unsafe.Pointer(&p.seg[off]))
+}
+
+func (p *SharedPool) next(slot int) *atomic.Uint64 {
+	return p.word(sharedHeaderLen + sharedMetaLen*slot)
+}
+
+func (p *SharedPool) owner(slot int) *atomic.Uint64 {
+	return p.word(sharedHeaderLen + sharedMetaLen*slot + 8)
+}
+
+// Under the creation lock, so no other process sees a partial header.
+func (p *SharedPool) init() {
+	p.word(sharedSlotSizeOff).Store(uint64(p.slotSize))
+	p.word(sharedSlotsOff).Store(uint64(p.slots))
+	for i := range p.slots - 1 {
+		p.next(i).Store(uint64(i + 2)) // slot i+1.
+	}
+	p.word(sharedHeadOff).Store(1)
+	p.word(sharedFreeOff).Store(uint64(p.slots))
+	p.word(sharedMagicOff).Store(sharedMagic)
+}
+
+func (p *SharedPool) validate(size int) error {
+	if len(p.seg) != size || p.word(sharedMagicOff).Load() != sharedMagic {
+		return fmt.Errorf("bytepool: shared segment of %d bytes not matching options", len(p.seg))
+	}
+	if p.word(sharedSlotSizeOff).Load() != uint64(p.slotSize) || p.word(sharedSlotsOff).Load() != uint64(p.slots) {
+		return errors.New("bytepool: shared segment created with other options")
+	}
+	return nil
+}
+
+// Lock free stack, the generation in the head's upper half preventing ABA across processes.
+func (p *SharedPool) pop() int {
+	head := p.word(sharedHeadOff)
+	for {
+		h := head.Load()
+		top := int(h & (1<<32 - 1))
+		if top == 0 {
+			return -1
+		}
+		next := p.next(top - 1).Load()
+		if head.CompareAndSwap(h, (h>>32+1)<<32|next) {
+			p.word(sharedFreeOff).Add(^uint64(0))
+			return top - 1
+		}
+	}
+}
+
+func (p *SharedPool) push(slot int) {
+	head := p.word(sharedHeadOff)
+	for {
+		h := head.Load()
+		p.next(slot).Store(h & (1<<32 - 1))
+		if head.CompareAndSwap(h, (h>>32+1)<<32|uint64(slot+1)) {
+			p.word(sharedFreeOff).Add(1)
+			return
+		}
+	}
+}
+
+func (p *SharedPool) GetGrown(c int) *Bytes {
+	p.gets.Add(1)
+	if c <= p.slotSize {
+		if b := p.getSlot(); b != nil {
+			return b
+		}
+	}
+	p.overs.Add(1)
+	return &Bytes{B: make([]byte, 0, c), pool: p}
+}
+
+// nil when empty or closed.
+func (p *SharedPool) getSlot() *Bytes {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return nil
+	}
+	slot := p.pop()
+	if slot < 0 {
+		return nil
+	}
+	p.owner(slot).Store(p.pid)
+	p.hits.Add(1)
+	off := p.dataOff + slot*p.slotSize
+	return &Bytes{B: p.seg[off : off : off+p.slotSize], pool: p, view: true}
+}
+
+func (p *SharedPool) GetFilled(length int) *Bytes {
+	b := p.GetGrown(length)
+	b.B = b.B[:length]
+	return b
+}
+
+// slot of b, or -1 when b is not in the segment.
+func (p *SharedPool) slotOf(b []byte) int {
+	if cap(b) != p.slotSize {
+		return -1
+	}
+	off := uintptr(unsafe.Pointer(unsafe.SliceData(b))) - p.data // wraps when below.
+	if off >= uintptr(p.slots*p.slotSize) || off%uintptr(p.slotSize) != 0 {
+		return -1
+	}
+	return int(off) / p.slotSize
+}
+
+func (p *SharedPool) put(b *Bytes) {
+	if b == nil {
+		return
+	}
+	b.pool = nil
+	slot := p.slotOf(b.B)
+	if slot < 0 {
+		b.debug.released(b.B)
+		return // over or replaced B.
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		b.B = nil
+		return
+	}
+	b.debug.released(b.B) // here as Release skips it, the slot being unmapped by Close.
+	b.B = nil
+	if p.owner(slot).CompareAndSwap(p.pid, 0) { // else already reclaimed.
+		p.push(slot)
+	}
+}
+
+// Frees the slots of processes that exited without releasing them, returning how many.
+// Best effort: a slot is leaked if its process exits between getting it and recording
+// ownership, and kept if the pid is reused.
+func (p *SharedPool) Reclaim() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return 0
+	}
+	var n int
+	for slot := range p.slots {
+		pid := p.owner(slot).Load()
+		if pid == 0 || pid == p.pid || processAlive(int(pid)) {
+			continue
+		}
+		if p.owner(slot).CompareAndSwap(pid, 0) {
+			p.push(slot)
+			n++
+		}
+	}
+	return n
+}
+
+func (p *SharedPool) Stats() SharedPoolStats {
+	s := SharedPoolStats{
+		SlotSize: p.slotSize,
+		Slots:    p.slots,
+		Gets:     p.gets.Load(),
+		Hits:     p.hits.Load(),
+		Overs:    p.overs.Load(),
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.closed {
+		s.Free = int(p.word(sharedFreeOff).Load())
+	}
+	return s
+}
+
+// Unmaps the segment once concurrent calls finish, leaving it for other processes.
+// Outstanding Bytes from the segment must not be used after, and are dropped on release.
+func (p *SharedPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	return unmapShared(p.seg)
+}
@@ -0,0 +1,56 @@
+//go:build linux
+
+package bytepool
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// Maps the file at path, sized to size when created. init runs under an exclusive flock,
+// so concurrent openers see either no segment or an initialized one.
+func mapShared(path string, size int, init func(seg []byte, created bool) error) ([]byte, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // the mapping outlives the descriptor.
+
+	fd := int(f.Fd())
+	if err := syscall.Flock(fd, syscall.LOCK_EX); err != nil {
+		return nil, err
+	}
+	defer syscall.Flock(fd, syscall.LOCK_UN) // closing would also unlock.
+
+	st, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	created := st.Size() == 0
+	if created {
+		if err := f.Truncate(int64(size)); err != nil {
+			return nil, err
+		}
+	} else if st.Size() != int64(size) {
+		size = int(st.Size()) // mapped so init reports the mismatch.
+	}
+
+	seg, err := syscall.Mmap(fd, 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+	if err := init(seg, created); err != nil {
+		_ = syscall.Munmap(seg)
+		return nil, err
+	}
+	return seg, nil
+}
+
+func unmapShared(seg []byte) error {
+	return syscall.Munmap(seg)
+}
+
+func processAlive(pid int) bool {
+	return !errors.Is(syscall.Kill(pid, 0), syscall.ESRCH)
+}
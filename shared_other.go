@@ -0,0 +1,15 @@
+//go:build !linux
+
+package bytepool
+
+func mapShared(string, int, func([]byte, bool) error) ([]byte, error) {
+	return nil, ErrSharedUnsupported
+}
+
+func unmapShared([]byte) error {
+	return nil
+}
+
+func processAlive(int) bool {
+	return true
+}
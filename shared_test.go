@@ -0,0 +1,154 @@
+//go:build linux
+
+package bytepool_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestShared(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "seg")
+	o := bytepool.SharedOptions{SlotSize: 100, Slots: 2}
+
+	p1, err := bytepool.OpenShared(path, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p1.Close()
+	p2, err := bytepool.OpenShared(path, o) // a second mapping, as another process would have.
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p2.Close()
+
+	b := p1.GetGrown(50)
+	diffFatal(t, 100, cap(b.B))
+	diffFatal(t, 1, p2.Stats().Free)
+
+	requirePanic(t, func() { bytepool.NewBucket(8, 128).Put(b) }) // a view.
+	diffFatal(t, 1, p2.Stats().Free)
+	noPanics := bytepool.NewBucketOptions([]int{8, 128}, bytepool.BucketPoolOptions{NoPanics: true})
+	noPanics.Put(b) // released where it stays.
+	diffFatal(t, 2, p2.Stats().Free)
+	diffFatal(t, uint64(1), noPanics.Stats().Misuses)
+
+	b1 := p2.GetFilled(3)
+	b2 := p2.GetGrown(1)
+	b3 := p2.GetGrown(1) // empty, allocated.
+	b4 := p2.GetGrown(101)
+	diffFatal(t, []int{100, 100}, []int{cap(b1.B), cap(b2.B)}) // slots, got through p2.
+	diffFatal(t, bytepool.SharedPoolStats{SlotSize: 100, Slots: 2, Gets: 4, Hits: 2, Overs: 2}, p2.Stats())
+
+	for _, b := range []*bytepool.Bytes{b1, b2, b3, b4} {
+		b.Release()
+	}
+	diffFatal(t, 2, p1.Stats().Free)
+
+	if _, err := bytepool.OpenShared(path, bytepool.SharedOptions{SlotSize: 100, Slots: 3}); err == nil {
+		t.Fatal("opened with other options")
+	}
+}
+
+func TestShared_data(t *testing.T) {
+	requireNoDebug(t)
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "seg")
+	o := bytepool.SharedOptions{SlotSize: 100, Slots: 2}
+	p1, err := bytepool.OpenShared(path, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p1.Close()
+	p2, err := bytepool.OpenShared(path, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p2.Close()
+
+	b := p1.GetGrown(50)
+	b.B = append(b.B, "abc"...)
+	b.Release()
+
+	b = p2.GetFilled(3) // the slot just freed.
+	diffFatal(t, "abc", string(b.B))
+	b.Release()
+}
+
+func TestShared_Close(t *testing.T) {
+	t.Parallel()
+
+	p, err := bytepool.OpenShared(filepath.Join(t.TempDir(), "seg"), bytepool.SharedOptions{SlotSize: 10, Slots: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	held := p.GetGrown(1)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				b := p.GetFilled(10)
+				b.B[0] = 1
+				b.Release()
+				p.Reclaim()
+				p.Stats()
+			}
+		}()
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	held.Release() // dropped.
+	diffFatal(t, 0, p.Stats().Free)
+	diffFatal(t, 0, p.Reclaim())
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestShared_Reclaim(t *testing.T) {
+	if path := os.Getenv("BYTEPOOL_SHARED_CHILD"); path != "" {
+		p, err := bytepool.OpenShared(path, bytepool.SharedOptions{SlotSize: 10, Slots: 3})
+		if err != nil {
+			os.Exit(1)
+		}
+		p.GetGrown(1)
+		p.GetGrown(1)
+		os.Exit(0) // without releasing.
+	}
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "seg")
+	p, err := bytepool.OpenShared(path, bytepool.SharedOptions{SlotSize: 10, Slots: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	held := p.GetGrown(1)
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestShared_Reclaim$")
+	cmd.Env = append(os.Environ(), "BYTEPOOL_SHARED_CHILD="+path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatal(err, string(out))
+	}
+	diffFatal(t, 0, p.Stats().Free)
+
+	diffFatal(t, 2, p.Reclaim()) // not our own.
+	diffFatal(t, 2, p.Stats().Free)
+	held.Release()
+	diffFatal(t, 3, p.Stats().Free)
+}
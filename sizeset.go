@@ -0,0 +1,170 @@
+package bytepool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Bucket sizes, sorted, without repeats and each >= 1 when made by NewSizeSet, ParseSizeSet or
+// the generators. Usable wherever []int sizes are, such as NewBucketFull.
+//
+// Parses from and formats to text such as for config files, see ParseSizeSet. Encodes to JSON as
+// an array, decoding from either an array or a string.
+type SizeSet []int
+
+// Sorts and removes repeats. Errors if empty or a size < 1.
+func NewSizeSet(sizes ...int) (SizeSet, error) {
+	if len(sizes) == 0 {
+		return nil, errors.New("bytepool: empty sizes")
+	}
+	s := slices.Clone(sizes)
+	slices.Sort(s)
+	if s[0] < 1 {
+		return nil, fmt.Errorf("bytepool: size %d < 1", s[0])
+	}
+	return slices.Compact(s), nil
+}
+
+// Parses a comma separated list of sizes, or a generator as:
+//
+//	pow2:MIN:MAX
+//	linear:MIN:MAX:BUCKETS
+//	expo:MIN:MAX:BUCKETS
+//
+// Sizes can have a k, m or g suffix for powers of 1024, such as "expo:8:64k:20" or "512,4k,1m".
+func ParseSizeSet(s string) (SizeSet, error) {
+	kind, args, found := strings.Cut(s, ":")
+	if !found {
+		var sizes []int
+		for _, f := range strings.Split(s, ",") {
+			v, err := parseSize(f)
+			if err != nil {
+				return nil, err
+			}
+			sizes = append(sizes, v)
+		}
+		return NewSizeSet(sizes...)
+	}
+
+	fields := strings.Split(args, ":")
+	want := 3
+	if kind == "pow2" {
+		want = 2
+	}
+	if len(fields) != want {
+		return nil, fmt.Errorf("bytepool: size set %q: want %d arguments", s, want)
+	}
+	var nums []int
+	for _, f := range fields {
+		v, err := parseSize(f)
+		if err != nil {
+			return nil, err
+		}
+		nums = append(nums, v)
+	}
+	minSize, maxSize := nums[0], nums[1]
+	if minSize < 1 {
+		return nil, fmt.Errorf("bytepool: size set %q: min < 1", s)
+	}
+	if maxSize <= minSize {
+		return nil, fmt.Errorf("bytepool: size set %q: max <= min", s)
+	}
+	if maxSize > math.MaxInt/2 { // generators double past max.
+		return nil, fmt.Errorf("bytepool: size set %q: max too large", s)
+	}
+	if want == 3 && nums[2] < 2 {
+		return nil, fmt.Errorf("bytepool: size set %q: buckets < 2", s)
+	}
+
+	switch kind {
+	case "pow2":
+		return Pow2Sizes(minSize, maxSize), nil
+	case "linear":
+		return LinearSizes(minSize, maxSize, nums[2]), nil
+	case "expo":
+		return ExpoSizes(minSize, maxSize, nums[2]), nil
+	}
+	return nil, fmt.Errorf("bytepool: size set %q: unknown generator %q", s, kind)
+}
+
+func parseSize(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	mult := 1
+	if n := len(s); n > 0 {
+		switch s[n-1] {
+		case 'k', 'K':
+			mult = 1 << 10
+		case 'm', 'M':
+			mult = 1 << 20
+		case 'g', 'G':
+			mult = 1 << 30
+		}
+		if mult > 1 {
+			s = s[:n-1]
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("bytepool: size: %w", err)
+	}
+	if v < 0 || v > math.MaxInt/mult {
+		return 0, fmt.Errorf("bytepool: size %q out of range", s)
+	}
+	return v * mult, nil
+}
+
+// Comma separated sizes, parsable by ParseSizeSet.
+func (s SizeSet) String() string {
+	var b strings.Builder
+	for i, v := range s {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Itoa(v))
+	}
+	return b.String()
+}
+
+func (s SizeSet) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *SizeSet) UnmarshalText(text []byte) error {
+	return s.Set(string(text))
+}
+
+// Parses as ParseSizeSet, implementing flag.Value.
+func (s *SizeSet) Set(text string) error {
+	v, err := ParseSizeSet(text)
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+func (s SizeSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]int(s))
+}
+
+func (s *SizeSet) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return s.Set(text)
+	}
+	var sizes []int
+	if err := json.Unmarshal(data, &sizes); err != nil {
+		return fmt.Errorf("bytepool: size set: want array or string: %w", err)
+	}
+	v, err := NewSizeSet(sizes...)
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
@@ -0,0 +1,121 @@
+package bytepool_test
+
+import (
+	"encoding/json"
+	"flag"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestParseSizeSet(t *testing.T) {
+	t.Parallel()
+
+	type sizeCase struct {
+		in   string
+		want bytepool.SizeSet
+	}
+	cases := []sizeCase{
+		{"8", bytepool.SizeSet{8}},
+		{"64, 8,8,1k", bytepool.SizeSet{8, 64, 1024}},
+		{"1m,1G", bytepool.SizeSet{1 << 20, 1 << 30}},
+		{"pow2:8:64", bytepool.Pow2Sizes(8, 64)},
+		{"linear:8:64:4", bytepool.LinearSizes(8, 64, 4)},
+		{"expo:8:64k:20", bytepool.ExpoSizes(8, 64<<10, 20)},
+	}
+	if strconv.IntSize == 64 {
+		g := 1 << 30
+		cases = append(cases, sizeCase{"1m,2G", bytepool.SizeSet{1 << 20, 2 * g}})
+	}
+	for _, c := range cases {
+		got, err := bytepool.ParseSizeSet(c.in)
+		if err != nil {
+			t.Fatal(c.in, err)
+		}
+		diffFatal(t, c.want, got)
+
+		again, err := bytepool.ParseSizeSet(got.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		diffFatal(t, got, again)
+	}
+
+	for _, in := range []string{"", "0", "8,x", "expo:8:64", "expo:64:8:4", "pow2:0:8", "linear:8:64:1", "other:8:64:4", "expo:8:64:x",
+		"9000000000G", "-9000000000G", "pow2:1:" + strconv.Itoa(math.MaxInt/2+1)} {
+		if _, err := bytepool.ParseSizeSet(in); err == nil {
+			t.Fatal("expected error", in)
+		}
+	}
+}
+
+func TestNewSizeSet(t *testing.T) {
+	t.Parallel()
+
+	got, err := bytepool.NewSizeSet(32, 8, 32, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, bytepool.SizeSet{8, 16, 32}, got)
+
+	if _, err := bytepool.NewSizeSet(); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := bytepool.NewSizeSet(8, 0); err == nil {
+		t.Fatal("expected error")
+	}
+
+	pool := bytepool.NewBucketFull(got)
+	diffFatal(t, 16, cap(pool.GetGrown(9).B))
+}
+
+func TestSizeSet_json(t *testing.T) {
+	t.Parallel()
+
+	var config struct {
+		A bytepool.SizeSet
+		B bytepool.SizeSet
+	}
+	if err := json.Unmarshal([]byte(`{"A":[64,8],"B":"pow2:8:32"}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, bytepool.SizeSet{8, 64}, config.A)
+	diffFatal(t, bytepool.SizeSet{8, 16, 32}, config.B)
+
+	out, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, `{"A":[8,64],"B":[8,16,32]}`, string(out))
+
+	if err := json.Unmarshal([]byte(`{"A":{}}`), &config); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := json.Unmarshal([]byte(`{"A":[0]}`), &config); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSizeSet_flag(t *testing.T) {
+	t.Parallel()
+
+	var sizes bytepool.SizeSet
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&sizes, "sizes", "")
+	if err := fs.Parse([]string{"-sizes", "expo:8:1k:4"}); err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, bytepool.ExpoSizes(8, 1024, 4), sizes)
+
+	text, err := sizes.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back bytepool.SizeSet
+	if err := back.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, sizes, back)
+}
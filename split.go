@@ -0,0 +1,73 @@
+package bytepool
+
+import (
+	"sync/atomic"
+)
+
+// Routes Gets between two pool configurations in the same process, so a tuning change (B)
+// can be compared against the current one (A) on mirrored traffic before switching.
+// Bytes release to the pool they were taken from.
+type Split struct {
+	a, b     SizedPooler
+	percentB atomic.Int64
+	n        atomic.Uint64
+	getsA    atomic.Uint64
+	getsB    atomic.Uint64
+}
+
+// percentB of Gets route to b, evenly interleaved. Panics if percentB is not within [0,100].
+func NewSplit(a, b SizedPooler, percentB int) *Split {
+	s := &Split{a: a, b: b}
+	s.SetPercentB(percentB)
+	return s
+}
+
+// Changes the share routed to b, such as ramping up a validated configuration.
+// Panics if percentB is not within [0,100].
+func (s *Split) SetPercentB(percentB int) {
+	if percentB < 0 || percentB > 100 {
+		panic("percentB must be within [0,100]")
+	}
+	s.percentB.Store(int64(percentB))
+}
+
+func (s *Split) GetGrown(c int) *Bytes {
+	return s.choose().GetGrown(c)
+}
+
+func (s *Split) GetFilled(length int) *Bytes {
+	return s.choose().GetFilled(length)
+}
+
+func (s *Split) choose() SizedPooler {
+	n := s.n.Add(1)
+	p := uint64(s.percentB.Load())
+	// b when the running share crosses a whole Get, spreading b evenly rather than in runs.
+	if n*p/100 != (n-1)*p/100 {
+		s.getsB.Add(1)
+		return s.b
+	}
+	s.getsA.Add(1)
+	return s.a
+}
+
+type SplitStats struct {
+	GetsA uint64
+	GetsB uint64
+	A     []Metric // nil when A is not a MetricsReader.
+	B     []Metric // nil when B is not a MetricsReader.
+}
+
+func (s *Split) Stats() SplitStats {
+	st := SplitStats{
+		GetsA: s.getsA.Load(),
+		GetsB: s.getsB.Load(),
+	}
+	if mr, ok := s.a.(MetricsReader); ok {
+		st.A = mr.AppendMetrics(nil)
+	}
+	if mr, ok := s.b.(MetricsReader); ok {
+		st.B = mr.AppendMetrics(nil)
+	}
+	return st
+}
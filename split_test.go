@@ -0,0 +1,58 @@
+package bytepool_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestSplit(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	a := bytepool.NewBucket(2, 64)
+	b := bytepool.NewBucket(2, 128)
+	s := bytepool.NewSplit(a, b, 25)
+
+	for range 100 {
+		s.GetGrown(3).Release()
+	}
+
+	st := s.Stats()
+	diffFatal(t, uint64(75), st.GetsA)
+	diffFatal(t, uint64(25), st.GetsB)
+	diffFatal(t, uint64(75), a.Stats().Gets)
+	diffFatal(t, uint64(25), b.Stats().Gets)
+	diffFatal(t, a.AppendMetrics(nil), st.A)
+	diffFatal(t, b.AppendMetrics(nil), st.B)
+
+	s.SetPercentB(100)
+	s.GetFilled(3).Release()
+	diffFatal(t, uint64(26), s.Stats().GetsB)
+}
+
+func TestSplit_interleaved(t *testing.T) {
+	t.Parallel()
+
+	s := bytepool.NewSplit(bytepool.NewSync(), bytepool.NewSync(), 50)
+
+	for i := range 10 {
+		s.GetGrown(3).Release()
+		st := s.Stats()
+		diffFatal(t, uint64(i+1)/2, st.GetsB)
+	}
+	if s.Stats().B != nil {
+		t.Fatal("expected nil metrics")
+	}
+}
+
+func TestSplit_invalid(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	bytepool.NewSplit(bytepool.NewSync(), bytepool.NewSync(), 101)
+}
@@ -0,0 +1,34 @@
+//go:build bytepool_nostats
+
+package bytepool
+
+// Counters and gates are no-ops, Stats return zero values and GCReport has no Survived.
+const statsEnabled = false
+
+type statsGate struct{}
+
+func (g *statsGate) enter() int { return 0 }
+
+func (g *statsGate) exit(int) {}
+
+func (g *statsGate) seal() {}
+
+func (g *statsGate) unseal() {}
+
+func readGates(read func(), _ ...*statsGate) { read() }
+
+type counter struct{}
+
+func (c *counter) Add(uint64) uint64 { return 0 }
+
+func (c *counter) Load() uint64 { return 0 }
+
+func (c *counter) Store(uint64) {}
+
+type gauge struct{}
+
+func (g *gauge) Add(int64) int64 { return 0 }
+
+func (g *gauge) Load() int64 { return 0 }
+
+func (g *gauge) Store(int64) {}
@@ -0,0 +1,31 @@
+//go:build bytepool_nostats
+
+package bytepool_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+// Skips tests asserting Stats values, which are zero without stats.
+func requireStats(t *testing.T) {
+	t.Helper()
+	t.Skip("asserts Stats, off with bytepool_nostats")
+}
+
+func TestBucket_Stats_nostats(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{MaxRetained: 1})
+	pooler := pool.Pooler(bytepool.BucketPoolerOptions{})
+	pool.GetGrown(4).Release()
+	pool.GetGrown(100).Release()
+	pooler.Get().Release()
+
+	diffFatal(t, bytepool.BucketPoolStats{}, pool.Stats())
+	diffFatal(t, bytepool.BucketPoolerStats{}, pooler.Stats())
+
+	// retention is unaffected.
+	diffFatal(t, []bytepool.BucketContents{{Size: 8, Pooled: 1, Exact: true}}, pool.Inspect())
+}
@@ -0,0 +1,128 @@
+//go:build !bytepool_nostats
+
+package bytepool
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Stats are compiled out with the bytepool_nostats build tag.
+const statsEnabled = true
+
+const (
+	gateStripes = 8 // power of 2.
+	gateEnter   = 1<<32 + 1
+	gateTries   = 4 // optimistic reads before sealing.
+)
+
+// Makes Stats an internally consistent snapshot. Counter updates enter one of the gate's
+// stripes, so concurrent updates rarely share a cache line. Stats reads optimistically,
+// retrying while any stripe was entered during the read, and seals the gate exclusively only
+// after a few retries so a busy pool can't starve it.
+type statsGate struct {
+	stripes [gateStripes]struct {
+		entered atomic.Uint64 // entries above 32 bits, in flight below.
+		_       [56]byte
+	}
+	sealed atomic.Bool
+	mu     sync.Mutex // held while sealed.
+}
+
+// Returns the stripe to exit.
+func (g *statsGate) enter() int {
+	i := int(rand.Uint32() & (gateStripes - 1))
+	s := &g.stripes[i].entered
+	for {
+		s.Add(gateEnter)
+		if !g.sealed.Load() {
+			return i
+		}
+		s.Add(^uint64(0))
+		g.mu.Lock() // wait out the seal.
+		g.mu.Unlock()
+	}
+}
+
+func (g *statsGate) exit(i int) {
+	g.stripes[i].entered.Add(^uint64(0))
+}
+
+// Excludes updates, waiting for those in flight.
+func (g *statsGate) seal() {
+	g.mu.Lock()
+	g.sealed.Store(true)
+	for i := range g.stripes {
+		for uint32(g.stripes[i].entered.Load()) != 0 {
+			runtime.Gosched()
+		}
+	}
+}
+
+func (g *statsGate) unseal() {
+	g.sealed.Store(false)
+	g.mu.Unlock()
+}
+
+// Entries so far, false with any in flight.
+func (g *statsGate) begin(entries *[gateStripes]uint64) bool {
+	for i := range g.stripes {
+		e := g.stripes[i].entered.Load()
+		if uint32(e) != 0 {
+			return false
+		}
+		entries[i] = e
+	}
+	return true
+}
+
+// Whether nothing entered since begin.
+func (g *statsGate) unchanged(entries *[gateStripes]uint64) bool {
+	for i := range g.stripes {
+		if g.stripes[i].entered.Load() != entries[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Calls read until it runs without any update entering gates, as a snapshot.
+func readGates(read func(), gates ...*statsGate) {
+	entries := make([][gateStripes]uint64, len(gates))
+	for range gateTries {
+		ok := true
+		for i, g := range gates {
+			ok = ok && g.begin(&entries[i])
+		}
+		if !ok {
+			runtime.Gosched()
+			continue
+		}
+		read()
+		for i, g := range gates {
+			ok = ok && g.unchanged(&entries[i])
+		}
+		if ok {
+			return
+		}
+	}
+	for _, g := range gates {
+		g.seal()
+	}
+	read()
+	for _, g := range gates {
+		g.unseal()
+	}
+}
+
+// Only read by Stats.
+type counter struct {
+	atomic.Uint64
+}
+
+// Only read by Stats.
+type gauge struct {
+	atomic.Int64
+}
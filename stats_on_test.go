@@ -0,0 +1,8 @@
+//go:build !bytepool_nostats
+
+package bytepool_test
+
+import "testing"
+
+// Skips tests asserting Stats values without stats, see stats_off_test.go.
+func requireStats(*testing.T) {}
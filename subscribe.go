@@ -0,0 +1,129 @@
+package bytepool
+
+import (
+	"sync"
+	"time"
+)
+
+// Metrics of every registered pool at one time.
+type StatsSnapshot struct {
+	Time    time.Time
+	Elapsed time.Duration // since the previous snapshot of the schedule, zero for the first.
+	Pools   map[string][]MetricSample
+}
+
+type MetricSample struct {
+	Metric
+	Delta uint64 // increase of a Cumulative Value since the previous snapshot, all of it when new or reset.
+}
+
+var subscriptions = struct {
+	mu        sync.Mutex
+	schedules map[time.Duration]*schedule
+}{schedules: make(map[time.Duration]*schedule)}
+
+// one ticker and collection shared by subscribers of an interval.
+type schedule struct {
+	subs map[*subscriber]struct{} // under subscriptions.mu.
+	stop chan struct{}
+}
+
+type subscriber struct {
+	fn func(StatsSnapshot)
+}
+
+// Calls fn every interval with a snapshot of the pools from Register, until the returned
+// cancel is called. Subscribers of the same interval share one schedule and collection, so
+// they see the same snapshots. fn is called from one goroutine per interval and must not block.
+func Subscribe(interval time.Duration, fn func(StatsSnapshot)) (cancel func()) {
+	if interval <= 0 {
+		panic("interval <= 0")
+	}
+	sub := &subscriber{fn: fn}
+
+	subscriptions.mu.Lock()
+	defer subscriptions.mu.Unlock()
+
+	s := subscriptions.schedules[interval]
+	if s == nil {
+		s = &schedule{subs: make(map[*subscriber]struct{}), stop: make(chan struct{})}
+		subscriptions.schedules[interval] = s
+		go s.run(interval)
+	}
+	s.subs[sub] = struct{}{}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			subscriptions.mu.Lock()
+			defer subscriptions.mu.Unlock()
+
+			delete(s.subs, sub)
+			if len(s.subs) == 0 {
+				close(s.stop)
+				delete(subscriptions.schedules, interval)
+			}
+		})
+	}
+}
+
+func (s *schedule) run(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	var last time.Time
+	prev := make(map[string]map[string]uint64) // cumulative values by pool then metric.
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-t.C:
+			snap := collectSnapshot(now, prev)
+			if !last.IsZero() {
+				snap.Elapsed = now.Sub(last)
+			}
+			last = now
+
+			subscriptions.mu.Lock()
+			subs := make([]*subscriber, 0, len(s.subs))
+			for sub := range s.subs {
+				subs = append(subs, sub)
+			}
+			subscriptions.mu.Unlock()
+
+			for _, sub := range subs {
+				sub.fn(snap)
+			}
+		}
+	}
+}
+
+// updates prev with the cumulative values of this snapshot.
+func collectSnapshot(now time.Time, prev map[string]map[string]uint64) StatsSnapshot {
+	snap := StatsSnapshot{Time: now, Pools: make(map[string][]MetricSample)}
+	pools := Registered()
+	for name, r := range pools {
+		last := prev[name]
+		next := make(map[string]uint64)
+		var samples []MetricSample
+		for _, m := range r.AppendMetrics(nil) {
+			sample := MetricSample{Metric: m}
+			if m.Cumulative {
+				sample.Delta = m.Value
+				if v, ok := last[m.Name]; ok && v <= m.Value {
+					sample.Delta = m.Value - v
+				}
+				next[m.Name] = m.Value
+			}
+			samples = append(samples, sample)
+		}
+		snap.Pools[name] = samples
+		prev[name] = next
+	}
+	for name := range prev {
+		if _, ok := pools[name]; !ok {
+			delete(prev, name)
+		}
+	}
+	return snap
+}
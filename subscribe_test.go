@@ -0,0 +1,54 @@
+package bytepool_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestSubscribe(t *testing.T) {
+	requireStats(t)
+	pool := bytepool.NewBucket(2, 8)
+	bytepool.Register("subscribe-test", pool)
+	defer bytepool.Unregister("subscribe-test")
+
+	gets := func(s bytepool.StatsSnapshot) bytepool.MetricSample {
+		for _, m := range s.Pools["subscribe-test"] {
+			if m.Name == "/bytepool/bucket/gets:calls" {
+				return m
+			}
+		}
+		t.Error("missing")
+		return bytepool.MetricSample{}
+	}
+
+	snaps1 := make(chan bytepool.StatsSnapshot, 100)
+	snaps2 := make(chan bytepool.StatsSnapshot, 100)
+	cancel1 := bytepool.Subscribe(time.Millisecond, func(s bytepool.StatsSnapshot) { snaps1 <- s })
+	cancel2 := bytepool.Subscribe(time.Millisecond, func(s bytepool.StatsSnapshot) { snaps2 <- s })
+	defer cancel2()
+
+	pool.GetGrown(3).Release()
+	pool.GetGrown(3).Release()
+
+	var total uint64
+	for total < 2 {
+		s := <-snaps1
+		m := gets(s)
+		total += m.Delta
+		diffFatal(t, total, m.Value)
+	}
+	cancel1()
+	cancel1() // idempotent
+
+	s1 := <-snaps2 // shared schedule continues.
+	for gets(s1).Value < 2 {
+		s1 = <-snaps2
+	}
+	s2 := <-snaps2
+	diffFatal(t, uint64(0), gets(s2).Delta)
+	if !s2.Time.After(s1.Time) || s2.Elapsed <= 0 {
+		t.Fatal(s1.Time, s2.Time, s2.Elapsed)
+	}
+}
@@ -11,7 +11,7 @@ type syncPool struct {
 // Suitable for similar sized Bytes otherwise pooled
 // Bytes can trend to the largest, wasting memory.
 // Direct sync.Pool implementation.
-func NewSync() Pooler {
+func NewSync() Pool {
 	return new(syncPool)
 }
 
@@ -22,6 +22,7 @@ func (p *syncPool) Get() *Bytes {
 		b = &Bytes{pool: p}
 	} else {
 		b = v.(*Bytes)
+		b.pool = p // could have been Put from another pool.
 	}
 	return b
 }
@@ -38,6 +39,19 @@ func (p *syncPool) GetFilled(len int) *Bytes {
 	return b
 }
 
+func (p *syncPool) Put(b *Bytes) {
+	if b != nil {
+		adopt(b, p)
+		b.Release()
+	}
+}
+
+func (p *syncPool) Adopt(b *Bytes) {
+	if b != nil {
+		adopt(b, p)
+	}
+}
+
 func (p *syncPool) put(b *Bytes) {
 	if b == nil {
 		return
@@ -0,0 +1,64 @@
+package bytepool
+
+import (
+	"io"
+)
+
+// Records written data into pooled Bytes while passing it through to writers, for the
+// "forward and also record" pattern. Close releases every recorded Bytes not taken.
+type TeeBuffer struct {
+	copies  []Appender
+	writers []io.Writer
+}
+
+// Records copies of written data, each in its own Bytes from p, and writes it to writers.
+// Panics if copies < 0.
+func NewTeeBuffer(p SizedPooler, copies int, writers ...io.Writer) *TeeBuffer {
+	if copies < 0 {
+		panic("copies < 0")
+	}
+	t := &TeeBuffer{
+		copies:  make([]Appender, copies),
+		writers: writers,
+	}
+	for i := range t.copies {
+		t.copies[i].p = p
+	}
+	return t
+}
+
+// Records p, then writes it to each writer in order, stopping at the first error as io.MultiWriter.
+func (t *TeeBuffer) Write(p []byte) (int, error) {
+	for i := range t.copies {
+		t.copies[i].Append(p...)
+	}
+	for _, w := range t.writers {
+		n, err := w.Write(p)
+		if err != nil {
+			return n, err
+		}
+		if n != len(p) {
+			return n, io.ErrShortWrite
+		}
+	}
+	return len(p), nil
+}
+
+// Recorded copy i, valid until the next Write, Take or Close.
+func (t *TeeBuffer) Bytes(i int) []byte {
+	return t.copies[i].Bytes()
+}
+
+// Hands copy i to the caller, who must Release it. Can return nil if nothing was written.
+// Later Writes record into a new Bytes.
+func (t *TeeBuffer) Take(i int) *Bytes {
+	return t.copies[i].Take()
+}
+
+// Releases every copy not taken. Writers are not closed.
+func (t *TeeBuffer) Close() error {
+	for i := range t.copies {
+		t.copies[i].Release()
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package bytepool_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestTeeBuffer(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(2, 64)
+	var w1, w2 bytes.Buffer
+	tee := bytepool.NewTeeBuffer(pool, 2, &w1, &w2)
+
+	for range 3 {
+		if _, err := tee.Write([]byte("abc")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	diffFatal(t, "abcabcabc", w1.String())
+	diffFatal(t, "abcabcabc", w2.String())
+	diffFatal(t, []byte("abcabcabc"), tee.Bytes(0))
+
+	taken := tee.Take(1)
+	diffFatal(t, "abcabcabc", string(taken.B))
+	if tee.Bytes(1) != nil {
+		t.Fatal("not taken")
+	}
+
+	if err := tee.Close(); err != nil {
+		t.Fatal(err)
+	}
+	taken.Release()
+
+	st := pool.Stats()
+	diffFatal(t, st.Gets, st.Puts)
+}
+
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write")
+}
+
+func TestTeeBuffer_writeErr(t *testing.T) {
+	t.Parallel()
+
+	var w bytes.Buffer
+	tee := bytepool.NewTeeBuffer(bytepool.NewSync(), 1, errWriter{}, &w)
+	defer tee.Close()
+
+	if _, err := tee.Write([]byte("abc")); err == nil {
+		t.Fatal("expected error")
+	}
+	diffFatal(t, "", w.String())
+	diffFatal(t, []byte("abc"), tee.Bytes(0)) // recorded regardless
+}
@@ -0,0 +1,169 @@
+package bytepool
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type TenantOptions struct {
+	MaxRetained    int // bytes each tenant retains on its own.
+	SharedRetained int // bytes any tenant can borrow once over MaxRetained.
+}
+
+// Isolated tenant pools over the sizes of one BucketPool. Each tenant retains up to its own
+// byte cap and borrows from a shared overflow while other tenants leave it unused,
+// avoiding both strict per tenant waste and noisy neighbors.
+type TenantGroup struct {
+	pool        *BucketPool
+	maxRetained int
+	sharedFree  atomic.Int64
+}
+
+func (p *BucketPool) TenantGroup(o TenantOptions) *TenantGroup {
+	g := &TenantGroup{
+		pool:        p,
+		maxRetained: max(0, o.MaxRetained),
+	}
+	g.sharedFree.Store(int64(max(0, o.SharedRetained)))
+	return g
+}
+
+// New tenant, use one per tenant.
+func (g *TenantGroup) New() *Tenant {
+	return &Tenant{
+		group: g,
+		lists: make([][]*Bytes, len(g.pool.pools)),
+	}
+}
+
+// A tenant pool from TenantGroup. Misses get from the underlying BucketPool.
+type Tenant struct {
+	group *TenantGroup
+
+	mu       sync.Mutex
+	lists    [][]*Bytes // by bucket, LIFO.
+	retained int
+	borrowed int // part of retained from shared.
+	hits     uint64
+	misses   uint64
+	drops    uint64
+}
+
+func (t *Tenant) GetGrown(c int) *Bytes {
+	idx, sp := t.group.pool.findPool(c)
+	if sp == nil {
+		b := t.group.pool.GetGrown(c)
+		b.pool = t
+		return b
+	}
+	if b := t.pop(idx); b != nil {
+		b.B = Sized(b.B, sp.size)
+		b.pool = t
+		return b
+	}
+	return sp.get(t)
+}
+
+func (t *Tenant) GetFilled(length int) *Bytes {
+	b := t.GetGrown(length)
+	b.B = b.B[:length]
+	return b
+}
+
+func (t *Tenant) Put(b *Bytes) {
+	if b != nil {
+		adopt(b, t)
+		b.Release()
+	}
+}
+
+func (t *Tenant) Adopt(b *Bytes) {
+	if b != nil {
+		adopt(b, t)
+	}
+}
+
+type TenantStats struct {
+	Retained int // bytes.
+	Borrowed int // bytes of Retained from the shared overflow.
+	Hits     uint64
+	Misses   uint64
+	Drops    uint64 // puts not retained due to caps.
+}
+
+func (t *Tenant) Stats() TenantStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return TenantStats{
+		Retained: t.retained,
+		Borrowed: t.borrowed,
+		Hits:     t.hits,
+		Misses:   t.misses,
+		Drops:    t.drops,
+	}
+}
+
+// nil when empty.
+func (t *Tenant) pop(idx int) *Bytes {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	l := t.lists[idx]
+	if len(l) == 0 {
+		t.misses++
+		return nil
+	}
+	b := l[len(l)-1]
+	l[len(l)-1] = nil
+	t.lists[idx] = l[:len(l)-1]
+	t.hits++
+
+	t.retained -= cap(b.B)
+	if over := max(0, t.retained-t.group.maxRetained); over < t.borrowed {
+		t.group.sharedFree.Add(int64(t.borrowed - over))
+		t.borrowed = over
+	}
+	return b
+}
+
+func (t *Tenant) put(b *Bytes) {
+	if b == nil {
+		return
+	}
+	pool := t.group.pool
+	idx, sp := pool.findPool(cap(b.B))
+
+	t.mu.Lock()
+	kept := sp != nil && t.reserve(cap(b.B))
+	if kept {
+		b.B = b.B[:0]
+		t.lists[idx] = append(t.lists[idx], b)
+	} else {
+		t.drops++
+	}
+	t.mu.Unlock()
+
+	switch {
+	case kept:
+	case sp == nil:
+		pool.discard(b)
+	default:
+		pool.dropped(sp, b) // memory and discards as if put to the pool.
+	}
+}
+
+// must hold mu.
+func (t *Tenant) reserve(size int) bool {
+	own := max(0, t.group.maxRetained-t.retained)
+	borrow := int64(max(0, size-own))
+	if borrow > 0 {
+		if t.group.sharedFree.Add(-borrow) < 0 {
+			t.group.sharedFree.Add(borrow)
+			return false
+		}
+	}
+	t.retained += size
+	t.borrowed += int(borrow)
+	return true
+}
@@ -0,0 +1,72 @@
+package bytepool_test
+
+import (
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestTenantGroup(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucketFull([]int{8, 16})
+	group := pool.TenantGroup(bytepool.TenantOptions{MaxRetained: 16, SharedRetained: 16})
+	t1 := group.New()
+	t2 := group.New()
+
+	get := func(tn *bytepool.Tenant, n int) []*bytepool.Bytes {
+		var bs []*bytepool.Bytes
+		for range n {
+			bs = append(bs, tn.GetGrown(16))
+		}
+		return bs
+	}
+	release := func(bs []*bytepool.Bytes) {
+		for _, b := range bs {
+			b.Release()
+		}
+	}
+
+	release(get(t1, 3)) // own 16, borrows 16, drops 1
+	diffFatal(t, bytepool.TenantStats{Retained: 32, Borrowed: 16, Misses: 3, Drops: 1}, t1.Stats())
+
+	release(get(t2, 2)) // own 16, shared exhausted
+	diffFatal(t, bytepool.TenantStats{Retained: 16, Misses: 2, Drops: 1}, t2.Stats())
+
+	get(t1, 1) // repays shared first
+	diffFatal(t, bytepool.TenantStats{Retained: 16, Hits: 1, Misses: 3, Drops: 1}, t1.Stats())
+
+	release(get(t2, 2)) // reuses one, borrows the repaid shared
+	diffFatal(t, bytepool.TenantStats{Retained: 32, Borrowed: 16, Hits: 1, Misses: 3, Drops: 1}, t2.Stats())
+
+	b := t1.GetFilled(20) // over
+	diffFatal(t, 20, len(b.B))
+	b.Release()
+	diffFatal(t, uint64(2), t1.Stats().Drops)
+}
+
+func TestTenantGroup_dropsUncounted(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8, 16}, bytepool.BucketPoolOptions{
+		MaxRetained: 10,
+		MemoryLimit: bytepool.MemoryLimit{Hard: 1000},
+	})
+	tn := pool.TenantGroup(bytepool.TenantOptions{MaxRetained: 16}).New()
+
+	var bs []*bytepool.Bytes
+	for range 3 {
+		bs = append(bs, tn.GetGrown(16))
+	}
+	bs = append(bs, tn.GetGrown(20)) // over
+	diffFatal(t, int64(3*16+20), pool.Stats().Memory)
+
+	for _, b := range bs {
+		b.Release()
+	}
+	diffFatal(t, bytepool.TenantStats{Retained: 16, Misses: 3, Drops: 3}, tn.Stats())
+	diffFatal(t, int64(16), pool.Stats().Memory) // only what the tenant retains.
+	bucket := pool.Stats().Buckets[0]
+	diffFatal(t, [2]uint64{16, 2}, [2]uint64{uint64(bucket.Size), bucket.Drops})
+}
@@ -0,0 +1,231 @@
+package bytepool
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Like Scanner, though tokens are views into the pooled buffers read into rather than copies.
+// A buffer returns to the pool once the Tokenizer has moved past it and all its tokens are
+// released, so a held token holds its whole buffer. Tokens a SplitFunc returns from outside
+// the buffer are copied.
+type Tokenizer struct {
+	r       io.Reader
+	pool    SizedPooler
+	split   bufio.SplitFunc
+	maxSize int
+
+	chunk      *tokenChunk // data is chunk.b.B[start:end], len(B) is the buffer size.
+	start, end int
+	token      *Bytes
+	err        error
+	done       bool
+	empties    int // consecutive empty tokens without advancing.
+}
+
+// Buffer of a Tokenizer, referenced by it and each of its tokens.
+type tokenChunk struct {
+	b    *Bytes
+	refs atomic.Int64
+}
+
+func newTokenChunk(b *Bytes) *tokenChunk {
+	c := &tokenChunk{b: b}
+	c.refs.Store(1)
+	return c
+}
+
+func (c *tokenChunk) unref() {
+	if c.refs.Add(-1) == 0 {
+		c.b.Release()
+	}
+}
+
+// Releases a token.
+func (c *tokenChunk) put(b *Bytes) {
+	b.pool = nil // so releasing twice does nothing.
+	b.B = nil
+	c.unref()
+}
+
+// Splits by lines, as bufio.ScanLines.
+func NewTokenizer(r io.Reader, p SizedPooler) *Tokenizer {
+	return &Tokenizer{
+		r:       r,
+		pool:    p,
+		split:   bufio.ScanLines,
+		maxSize: bufio.MaxScanTokenSize,
+	}
+}
+
+// Sets the split function. Panics if called after Scan.
+func (t *Tokenizer) Split(split bufio.SplitFunc) {
+	if t.chunk != nil || t.done {
+		panic("Split called after Scan")
+	}
+	t.split = split
+}
+
+// Sets the maximum buffer size, and so the maximum token size. Defaults to bufio.MaxScanTokenSize.
+// Panics if called after Scan.
+func (t *Tokenizer) Buffer(maxSize int) {
+	if t.chunk != nil || t.done {
+		panic("Buffer called after Scan")
+	}
+	t.maxSize = maxSize
+}
+
+// Advances to the next token, which is then available through Token.
+// Returns false at the end of input or on error.
+func (t *Tokenizer) Scan() bool {
+	t.token.Release() // not taken.
+	t.token = nil
+	if t.done {
+		return false
+	}
+
+	for {
+		if t.end > t.start || t.err != nil {
+			var data []byte
+			if t.chunk != nil {
+				data = t.chunk.b.B[t.start:t.end]
+			}
+			adv, tok, err := t.split(data, t.err != nil)
+			if err != nil {
+				if errors.Is(err, bufio.ErrFinalToken) {
+					t.setToken(tok)
+					t.finish(nil)
+					return tok != nil
+				}
+				t.finish(err)
+				return false
+			}
+			if adv < 0 {
+				t.finish(bufio.ErrNegativeAdvance)
+				return false
+			}
+			if adv > len(data) {
+				t.finish(bufio.ErrAdvanceTooFar)
+				return false
+			}
+			t.start += adv
+			if tok != nil {
+				if adv > 0 {
+					t.empties = 0
+				} else if t.empties++; t.empties > 100 {
+					panic("too many empty tokens without progressing")
+				}
+				t.setToken(tok)
+				return true
+			}
+		}
+		if t.err != nil {
+			t.finish(t.err)
+			return false
+		}
+		if !t.fill() {
+			return false
+		}
+	}
+}
+
+// Token from the last Scan, handed to the caller who must Release it. Its cap is its len, so
+// appending copies rather than overwriting the next token. Returns nil when already taken or
+// if Scan returned false. Tokens not taken are released by the next Scan.
+func (t *Tokenizer) Token() *Bytes {
+	tok := t.token
+	t.token = nil
+	return tok
+}
+
+// First non EOF error.
+func (t *Tokenizer) Err() error {
+	if errors.Is(t.err, io.EOF) {
+		return nil
+	}
+	return t.err
+}
+
+// Releases the Tokenizer's hold on its buffer and any token not taken, for stopping before
+// Scan returns false. Taken tokens stay valid until released.
+func (t *Tokenizer) Release() {
+	t.token.Release()
+	t.token = nil
+	t.finish(t.err)
+}
+
+func (t *Tokenizer) setToken(tok []byte) {
+	if tok == nil {
+		return
+	}
+	if t.chunk != nil {
+		if off, ok := offsetIn(t.chunk.b.B, tok); ok {
+			t.chunk.refs.Add(1)
+			t.token = &Bytes{B: t.chunk.b.B[off : off+len(tok) : off+len(tok)], pool: t.chunk, view: true}
+			return
+		}
+	}
+	t.token = t.pool.GetFilled(len(tok))
+	copy(t.token.B, tok)
+}
+
+// Offset of sub in buf, false when sub is not within it.
+func offsetIn(buf, sub []byte) (int, bool) {
+	if len(buf) == 0 || len(sub) > len(buf) {
+		return 0, false
+	}
+	off := uintptr(unsafe.Pointer(unsafe.SliceData(sub))) - uintptr(unsafe.Pointer(unsafe.SliceData(buf))) // wraps when below.
+	if off > uintptr(len(buf)-len(sub)) {
+		return 0, false
+	}
+	return int(off), true
+}
+
+func (t *Tokenizer) finish(err error) {
+	t.err = err
+	t.done = true
+	if t.chunk != nil {
+		t.chunk.unref()
+		t.chunk = nil
+	}
+	t.start, t.end = 0, 0
+}
+
+// reads more data, into a new buffer when full. Returns false when finished.
+func (t *Tokenizer) fill() bool {
+	if t.chunk == nil || t.end == len(t.chunk.b.B) {
+		var size int
+		if t.chunk != nil {
+			size = len(t.chunk.b.B)
+		}
+		if t.chunk == nil || t.start == 0 { // one token fills the buffer.
+			var ok bool
+			if size, ok = growScanBuffer(size, t.maxSize); !ok {
+				t.finish(bufio.ErrTooLong)
+				return false
+			}
+		}
+
+		if t.chunk != nil && t.start > 0 && t.chunk.refs.Load() == 1 {
+			// no tokens out, and only the Tokenizer adds references.
+			copy(t.chunk.b.B, t.chunk.b.B[t.start:t.end])
+		} else {
+			nc := newTokenChunk(t.pool.GetFilled(size))
+			if t.chunk != nil {
+				copy(nc.b.B, t.chunk.b.B[t.start:t.end])
+				t.chunk.unref()
+			}
+			t.chunk = nc
+		}
+		t.end -= t.start
+		t.start = 0
+	}
+
+	var n int
+	n, t.err = readScanBuffer(t.r, t.chunk.b.B[t.end:])
+	t.end += n
+	return true
+}
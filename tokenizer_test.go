@@ -0,0 +1,117 @@
+package bytepool_test
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestTokenizer(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	long := strings.Repeat("x", 10000)
+	input := "a\nbb\r\n\n" + long + "\n" + strings.Repeat("yz\n", 3000) + "last"
+
+	pool := bytepool.NewBucketOptions(bytepool.Pow2Sizes(8, 1<<16), bytepool.BucketPoolOptions{MaxRetained: 8})
+	tz := bytepool.NewTokenizer(iotest.HalfReader(strings.NewReader(input)), pool)
+
+	var tokens []*bytepool.Bytes
+	for tz.Scan() {
+		tok := tz.Token()
+		diffFatal(t, len(tok.B), cap(tok.B))
+		tokens = append(tokens, tok)
+	}
+	if err := tz.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Split(strings.ReplaceAll(input, "\r", ""), "\n")
+	var got []string
+	for _, tok := range tokens { // all still valid, views of buffers.
+		got = append(got, string(tok.B))
+	}
+	diffFatal(t, want, got)
+
+	s := pool.Stats()
+	if s.Gets >= uint64(len(tokens)) {
+		t.Fatal("tokens copied", s.Gets)
+	}
+	if s.Outstanding == 0 {
+		t.Fatal("buffers not held by tokens")
+	}
+
+	for _, tok := range tokens {
+		tok.Release()
+		tok.Release() // no effect.
+	}
+	s = pool.Stats()
+	diffFatal(t, s.Gets, s.Puts)
+	diffFatal(t, int64(0), s.Outstanding)
+}
+
+func TestTokenizer_adopt(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{4096}, bytepool.BucketPoolOptions{MaxRetained: 2})
+	tz := bytepool.NewTokenizer(strings.NewReader("a b c"), pool)
+	tz.Split(bufio.ScanWords)
+
+	tz.Scan()
+	a := tz.Token()
+	tz.Scan() // b not taken, released.
+	tz.Release()
+	diffFatal(t, int64(1), pool.Stats().Outstanding)
+
+	requirePanic(t, func() { bytepool.NewSync().Put(a) }) // a view.
+	requirePanic(t, func() { bytepool.NewSync().Adopt(a) })
+	diffFatal(t, int64(1), pool.Stats().Outstanding)
+	bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{NoPanics: true}).Put(a) // released where it stays.
+	diffFatal(t, int64(0), pool.Stats().Outstanding)
+	if tz.Scan() {
+		t.Fatal("scan after release")
+	}
+}
+
+func TestTokenizer_tooLong(t *testing.T) {
+	t.Parallel()
+
+	tz := bytepool.NewTokenizer(strings.NewReader(strings.Repeat("x", 100)+"\n"), bytepool.NewSync())
+	tz.Buffer(10)
+	if tz.Scan() {
+		t.Fatal("expected no token")
+	}
+	if !errors.Is(tz.Err(), bufio.ErrTooLong) {
+		t.Fatal(tz.Err())
+	}
+}
+
+func TestTokenizer_noProgress(t *testing.T) {
+	t.Parallel()
+
+	// as Scanner, sharing its reads.
+	r := io.MultiReader(strings.NewReader("a\nb"), emptyReader{})
+	tz := bytepool.NewTokenizer(r, bytepool.NewSync())
+	var got []string
+	for tz.Scan() {
+		tok := tz.Token()
+		got = append(got, string(tok.B))
+		tok.Release()
+	}
+	diffFatal(t, []string{"a", "b"}, got)
+	if !errors.Is(tz.Err(), io.ErrNoProgress) {
+		t.Fatal(tz.Err())
+	}
+}
+
+type emptyReader struct{}
+
+func (emptyReader) Read([]byte) (int, error) {
+	return 0, nil
+}
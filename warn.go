@@ -0,0 +1,60 @@
+package bytepool
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	warnLimited = iota
+	warnLeaseExpired
+	warnMisuse
+	warnOvers
+	warnSoftLimit
+	warnHardLimit
+	warnDegraded
+	warnKinds
+)
+
+// Warnings of a pool's loggers, each kind at most once per interval, counting those suppressed.
+type warner struct {
+	logger   *slog.Logger
+	name     string
+	interval time.Duration
+	kinds    [warnKinds]struct {
+		last       atomic.Int64 // unix nanos.
+		suppressed atomic.Uint64
+	}
+}
+
+// nil without a logger.
+func newWarner(logger *slog.Logger, name string, interval time.Duration) *warner {
+	if logger == nil {
+		return nil
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &warner{logger: logger, name: name, interval: interval}
+}
+
+// Nil does nothing.
+func (w *warner) warn(kind int, msg string, attrs ...slog.Attr) {
+	if w == nil {
+		return
+	}
+	k := &w.kinds[kind]
+	now := time.Now().UnixNano()
+	last := k.last.Load()
+	if last != 0 && now-last < int64(w.interval) || !k.last.CompareAndSwap(last, now) {
+		k.suppressed.Add(1)
+		return
+	}
+	attrs = append(attrs,
+		slog.String("pool", w.name),
+		slog.Uint64("suppressed", k.suppressed.Swap(0)),
+	)
+	w.logger.LogAttrs(context.Background(), slog.LevelWarn, msg, attrs...)
+}
@@ -0,0 +1,63 @@
+package bytepool_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_logger(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	var (
+		mu  sync.Mutex
+		out bytes.Buffer
+	)
+	pool := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{
+		Name:        "test",
+		Logger:      slog.New(slog.NewJSONHandler(lockedWriter{&mu, &out}, nil)),
+		LogInterval: time.Hour,
+		AllocLimit:  bytepool.AllocLimit{PerSecond: 1, Policy: bytepool.AllocSmaller},
+	})
+	defer pool.Close()
+
+	for range 4 {
+		pool.GetGrown(8) // one allowed, then limited.
+	}
+	pool.GetGrown(9) // over, by the OverWarnLogger default.
+
+	b := pool.GetLeased(8, time.Millisecond)
+	deadline := time.Now().Add(10 * time.Second)
+	for pool.Stats().LeasesExpired == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("lease never expired")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	b.Release()
+
+	mu.Lock()
+	defer mu.Unlock()
+	var msgs []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var got map[string]any
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatal(err)
+		}
+		diffFatal(t, "test", got["pool"])
+		msgs = append(msgs, got["msg"].(string))
+	}
+	want := []string{
+		"bytepool allocations over AllocLimit", // once per LogInterval.
+		"bytepool overs exceeded threshold",
+		"bytepool lease expired before release",
+	}
+	diffFatal(t, want, msgs)
+}
@@ -0,0 +1,108 @@
+package bytepool
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Alerts when a pool degrades for a sustained period, such as after payloads outgrow its sizes.
+// On when OnDegraded or Logger is set.
+type WatchdogOptions struct {
+	MinHitRate  float64 // of bucket Gets, below is degraded. Defaults to 0.5.
+	MaxOverRate float64 // of all Gets, above is degraded. Defaults to 0.1.
+	MinGets     int     // intervals with fewer Gets are not judged. Defaults to 100.
+
+	Interval time.Duration // Defaults to 1 minute.
+	Sustain  int           // consecutive degraded intervals before alerting. Defaults to 3.
+
+	// Alerted once per degradation, again only after a healthy interval.
+	// Called from a background worker, stop it with BucketPool.Close.
+	OnDegraded func(WatchdogReport)
+	Logger     *slog.Logger // warns as OnDegraded.
+}
+
+// Rates of the last interval of a degradation.
+type WatchdogReport struct {
+	Pool      string // BucketPoolOptions.Name.
+	Gets      uint64
+	HitRate   float64
+	OverRate  float64
+	Intervals int // consecutive degraded intervals.
+}
+
+type watchdog struct {
+	o    WatchdogOptions
+	name string
+	warn *warner // nil without a Logger.
+
+	// only used by the worker.
+	last      BucketPoolStats
+	intervals int
+	alerted   bool
+}
+
+func newWatchdog(o WatchdogOptions, name string, w *warner) *watchdog {
+	if o.MinHitRate <= 0 {
+		o.MinHitRate = 0.5
+	}
+	if o.MaxOverRate <= 0 {
+		o.MaxOverRate = 0.1
+	}
+	if o.MinGets <= 0 {
+		o.MinGets = 100
+	}
+	if o.Interval <= 0 {
+		o.Interval = time.Minute
+	}
+	if o.Sustain <= 0 {
+		o.Sustain = 3
+	}
+	return &watchdog{o: o, name: name, warn: w}
+}
+
+func (w *watchdog) check(s BucketPoolStats) {
+	delta := func(cur, last uint64) uint64 {
+		if cur < last { // ResetStats
+			return cur
+		}
+		return cur - last
+	}
+	gets := delta(s.Gets, w.last.Gets)
+	hits := delta(s.Hits, w.last.Hits)
+	bucketGets := hits + delta(s.Misses, w.last.Misses)
+	w.last = s
+
+	if gets < uint64(w.o.MinGets) {
+		return // not judged, keeping the streak.
+	}
+	r := WatchdogReport{
+		Pool:     w.name,
+		Gets:     gets,
+		OverRate: float64(gets-min(gets, bucketGets)) / float64(gets),
+		HitRate:  1,
+	}
+	if bucketGets > 0 {
+		r.HitRate = float64(hits) / float64(bucketGets)
+	}
+	if r.HitRate >= w.o.MinHitRate && r.OverRate <= w.o.MaxOverRate {
+		w.intervals = 0
+		w.alerted = false
+		return
+	}
+	w.intervals++
+	r.Intervals = w.intervals
+	if w.alerted || w.intervals < w.o.Sustain {
+		return
+	}
+	w.alerted = true
+
+	w.warn.warn(warnDegraded, "bytepool degraded",
+		slog.Uint64("gets", r.Gets),
+		slog.Float64("hit_rate", r.HitRate),
+		slog.Float64("over_rate", r.OverRate),
+		slog.Int("intervals", r.Intervals),
+	)
+	if w.o.OnDegraded != nil {
+		w.o.OnDegraded(r)
+	}
+}
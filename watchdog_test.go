@@ -0,0 +1,78 @@
+package bytepool_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_watchdog(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	var (
+		mu  sync.Mutex
+		out bytes.Buffer
+	)
+	reports := make(chan bytepool.WatchdogReport, 10)
+	pool := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{
+		Name: "test",
+		Watchdog: bytepool.WatchdogOptions{
+			MinGets:    1,
+			Interval:   time.Millisecond,
+			Sustain:    2,
+			OnDegraded: func(r bytepool.WatchdogReport) { reports <- r },
+			Logger:     slog.New(slog.NewTextHandler(lockedWriter{&mu, &out}, nil)),
+		},
+	})
+	defer pool.Close()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			pool.GetGrown(8) // misses, never released
+			pool.GetGrown(9) // over
+		}
+	}()
+
+	r := <-reports
+	diffFatal(t, "test", r.Pool)
+	diffFatal(t, 2, r.Intervals)
+	if r.HitRate != 0 || r.OverRate < 0.4 || r.OverRate > 0.6 || r.Gets == 0 {
+		t.Fatal(r)
+	}
+
+	select {
+	case r := <-reports:
+		t.Fatal("alerted again while degraded", r)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := out.String(); strings.Count(got, "bytepool degraded") != 1 {
+		t.Fatal(got)
+	}
+}
+
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  *bytes.Buffer
+}
+
+func (w lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
@@ -0,0 +1,109 @@
+package bytepool
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Counters of a BucketPool over a recent window, from StatsEpoch.
+type WindowStats struct {
+	Window time.Duration // covered, short of the asked window while the pool is younger.
+
+	Gets     uint64
+	Puts     uint64
+	Hits     uint64
+	Misses   uint64
+	Overs    uint64
+	Drops    uint64
+	Trimmed  uint64
+	Bypassed uint64
+	Limited  uint64
+}
+
+// Ring of counter samples, one per epoch, back to the longest window.
+type statsWindows struct {
+	windows []time.Duration
+
+	mu      sync.Mutex
+	samples []windowSample // ring, oldest at next once full.
+	next    int
+}
+
+type windowSample struct {
+	t time.Time
+	c [9]uint64 // as WindowStats.
+}
+
+const maxWindowSamples = 4096
+
+func newStatsWindows(epoch time.Duration, windows []time.Duration) *statsWindows {
+	if len(windows) == 0 {
+		windows = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute}
+	}
+	n := int((slices.Max(windows)+epoch-1)/epoch) + 1
+	w := &statsWindows{
+		windows: slices.Clone(windows),
+		samples: make([]windowSample, 0, min(n, maxWindowSamples)),
+	}
+	w.samples = append(w.samples, windowSample{t: time.Now()}) // counters start at zero.
+	return w
+}
+
+func windowCounters(s BucketPoolStats) [9]uint64 {
+	return [9]uint64{s.Gets, s.Puts, s.Hits, s.Misses, s.Overs, s.Drops, s.Trimmed, s.Bypassed, s.Limited}
+}
+
+func (w *statsWindows) record(now time.Time, s BucketPoolStats) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	sample := windowSample{t: now, c: windowCounters(s)}
+	if len(w.samples) < cap(w.samples) {
+		w.samples = append(w.samples, sample)
+		return
+	}
+	w.samples[w.next] = sample
+	w.next = (w.next + 1) % len(w.samples)
+}
+
+// Deltas of s from the newest sample at least each window old, or the oldest.
+func (w *statsWindows) stats(now time.Time, s BucketPoolStats) []WindowStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cur := windowCounters(s)
+	ws := make([]WindowStats, len(w.windows))
+	for i, d := range w.windows {
+		base := w.samples[w.next] // oldest.
+		for j := range len(w.samples) {
+			sample := w.samples[(w.next+j)%len(w.samples)]
+			if now.Sub(sample.t) < d {
+				break
+			}
+			base = sample
+		}
+
+		var c [9]uint64
+		for k := range cur {
+			if cur[k] < base.c[k] { // ResetStats
+				c[k] = cur[k]
+			} else {
+				c[k] = cur[k] - base.c[k]
+			}
+		}
+		ws[i] = WindowStats{
+			Window:   now.Sub(base.t),
+			Gets:     c[0],
+			Puts:     c[1],
+			Hits:     c[2],
+			Misses:   c[3],
+			Overs:    c[4],
+			Drops:    c[5],
+			Trimmed:  c[6],
+			Bypassed: c[7],
+			Limited:  c[8],
+		}
+	}
+	return ws
+}
@@ -0,0 +1,58 @@
+package bytepool_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestBucket_statsWindows(t *testing.T) {
+	requireStats(t)
+	t.Parallel()
+
+	pool := bytepool.NewBucketOptions([]int{8}, bytepool.BucketPoolOptions{
+		MaxRetained:  4,
+		StatsEpoch:   5 * time.Millisecond,
+		StatsWindows: []time.Duration{20 * time.Millisecond, time.Hour},
+	})
+	defer pool.Close()
+
+	for range 3 {
+		pool.GetGrown(1).Release()
+	}
+	w := pool.Stats().Windows
+	diffFatal(t, 2, len(w))
+	diffFatal(t, uint64(3), w[1].Gets) // since creation.
+	if w[1].Window >= time.Hour {
+		t.Fatal("window beyond pool age", w[1].Window)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for pool.Stats().Windows[0].Gets != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("gets never left the short window")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	pool.GetGrown(1).Release()
+	pool.GetGrown(9).Release()
+	s := pool.Stats()
+	diffFatal(t, bytepool.WindowStats{Gets: 2, Puts: 2, Hits: 1, Overs: 2}, withoutWindow(s.Windows[0]))
+	diffFatal(t, bytepool.WindowStats{Gets: 5, Puts: 5, Hits: 3, Misses: 1, Overs: 2}, withoutWindow(s.Windows[1]))
+	diffFatal(t, uint64(5), s.Gets) // lifetime.
+}
+
+func withoutWindow(w bytepool.WindowStats) bytepool.WindowStats {
+	w.Window = 0
+	return w
+}
+
+func TestBucket_statsWindowsOff(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucketFull([]int{8})
+	pool.GetGrown(1).Release()
+	diffFatal(t, 0, len(pool.Stats().Windows))
+}
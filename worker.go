@@ -0,0 +1,19 @@
+package bytepool
+
+import (
+	"time"
+)
+
+// runs fn every interval until stop is closed.
+func runEvery(interval time.Duration, stop <-chan struct{}, fn func()) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			fn()
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package bytepool
+
+import (
+	"io"
+	"net"
+)
+
+// Writes bufs in order, using writev when w supports it (such as *net.TCPConn),
+// then releases every buf, including on errors and partial writes.
+// bufs can contain nil.
+func WriteRelease(w io.Writer, bufs ...*Bytes) (int64, error) {
+	defer func() {
+		for _, b := range bufs {
+			b.Release()
+		}
+	}()
+
+	nb := make(net.Buffers, 0, len(bufs))
+	for _, b := range bufs {
+		if b != nil && len(b.B) > 0 {
+			nb = append(nb, b.B)
+		}
+	}
+	return nb.WriteTo(w)
+}
@@ -0,0 +1,56 @@
+package bytepool_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/graxinc/bytepool"
+)
+
+func TestWriteRelease(t *testing.T) {
+	t.Parallel()
+
+	pool := bytepool.NewBucket(2, 64)
+
+	get := func() []*bytepool.Bytes {
+		var bufs []*bytepool.Bytes
+		for _, s := range []string{"ab", "", "cde"} {
+			b := pool.GetGrown(len(s))
+			b.B = append(b.B, s...)
+			bufs = append(bufs, b)
+		}
+		return append(bufs, nil)
+	}
+
+	var w bytes.Buffer
+	n, err := bytepool.WriteRelease(&w, get()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffFatal(t, int64(5), n)
+	diffFatal(t, "abcde", w.String())
+
+	wantErr := errors.New("write")
+	n, err = bytepool.WriteRelease(&limitWriter{n: 3, err: wantErr}, get()...)
+	if !errors.Is(err, wantErr) {
+		t.Fatal(err)
+	}
+	diffFatal(t, int64(3), n)
+}
+
+// writes up to n bytes then fails with err.
+type limitWriter struct {
+	n   int
+	err error
+}
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, w.err
+	}
+	w.n -= len(p)
+	return len(p), nil
+}